@@ -0,0 +1,48 @@
+package ellipse
+
+import "math"
+
+// Builder builds Ellipse using chainable setters.
+// Builder starts with a unit circle centered at the origin.
+type Builder struct {
+	x     float64
+	y     float64
+	a     float64
+	b     float64
+	angle float64
+}
+
+// Build returns new Builder
+func Build() *Builder {
+	return &Builder{a: 1.0, b: 1.0}
+}
+
+// Center sets the origin of the built Ellipse to [x,y].
+func (bd *Builder) Center(x, y float64) *Builder {
+	bd.x, bd.y = x, y
+	return bd
+}
+
+// Axes sets the lengths of semi-major/minor axis of the built Ellipse.
+func (bd *Builder) Axes(a, b float64) *Builder {
+	bd.a, bd.b = a, b
+	return bd
+}
+
+// Angle sets the rotation angle of the built Ellipse in radians.
+func (bd *Builder) Angle(rad float64) *Builder {
+	bd.angle = rad
+	return bd
+}
+
+// AngleDeg sets the rotation angle of the built Ellipse in degrees.
+func (bd *Builder) AngleDeg(deg float64) *Builder {
+	bd.angle = deg * math.Pi / 180
+	return bd
+}
+
+// Make creates new Ellipse from the builder parameters.
+// It returns error if either of the axis is not positive.
+func (bd *Builder) Make() (*Ellipse, error) {
+	return New(bd.x, bd.y, bd.a, bd.b, bd.angle)
+}
@@ -0,0 +1,39 @@
+package ellipse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	assert := assert.New(t)
+
+	ell, err := Build().Make()
+	assert.NoError(err)
+	assert.Equal(&Ellipse{a: 1.0, b: 1.0}, ell)
+
+	ell, err = Build().Center(1.0, 2.0).Axes(3.0, 4.0).Angle(math.Pi).Make()
+	assert.NoError(err)
+	assert.Equal(&Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 4.0, angle: math.Pi}, ell)
+
+	ell, err = Build().AngleDeg(90).Make()
+	assert.NoError(err)
+	assert.InDelta(math.Pi/2, ell.angle, 1e-12)
+
+	testCases := []struct {
+		a float64
+		b float64
+	}{
+		{0, 1.0},
+		{1.0, 0},
+		{-1.0, 1.0},
+	}
+
+	for _, tc := range testCases {
+		ell, err := Build().Axes(tc.a, tc.b).Make()
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
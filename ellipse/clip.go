@@ -0,0 +1,177 @@
+package ellipse
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/plot/plotter"
+)
+
+// IntersectLine returns the intersections of the ellipse boundary with the line passing through the points p1 and p2.
+// The intersections are ordered in the direction from p1 to p2. It returns no intersections if the line misses
+// the ellipse or if p1 and p2 are the same point and a single intersection if the line is tangent to the ellipse.
+func (e *Ellipse) IntersectLine(p1, p2 plotter.XY) plotter.XYs {
+	t0, t1, ok := e.lineParams(p1, p2)
+	if !ok {
+		return nil
+	}
+
+	if t0 == t1 {
+		return plotter.XYs{lerpXY(p1, p2, t0)}
+	}
+
+	return plotter.XYs{lerpXY(p1, p2, t0), lerpXY(p1, p2, t1)}
+}
+
+// lineParams returns the parameters t0 <= t1 of the intersections p1 + t*(p2-p1) of the line passing through
+// the points p1 and p2 with the ellipse boundary. It returns false if the line does not intersect the ellipse.
+func (e *Ellipse) lineParams(p1, p2 plotter.XY) (float64, float64, bool) {
+	// intersect the line with the unit circle in the coordinate system of the ellipse
+	u1, v1 := e.local(p1.X, p1.Y)
+	u2, v2 := e.local(p2.X, p2.Y)
+	u1, v1, du, dv := u1/e.a, v1/e.b, (u2-u1)/e.a, (v2-v1)/e.b
+
+	a := du*du + dv*dv
+	if a == 0 {
+		return 0, 0, false
+	}
+	b := u1*du + v1*dv
+	c := u1*u1 + v1*v1 - 1
+
+	disc := b*b - a*c
+	if disc < 0 {
+		return 0, 0, false
+	}
+	sq := math.Sqrt(disc)
+
+	return (-b - sq) / a, (-b + sq) / a, true
+}
+
+// ClipPolyline clips the polyline pts to the ellipse interior.
+// It returns the parts of the polyline which lie inside the ellipse, each of which starts and ends either
+// at a polyline vertex or at the intersection of a polyline segment with the ellipse boundary.
+// It returns no parts if none of the polyline segments intersects the ellipse interior.
+func (e *Ellipse) ClipPolyline(pts plotter.XYs) []plotter.XYs {
+	var parts []plotter.XYs
+	var part plotter.XYs
+
+	for i := 0; i+1 < len(pts); i++ {
+		p1, p2 := pts[i], pts[i+1]
+
+		t0, t1, ok := e.lineParams(p1, p2)
+		switch {
+		case ok:
+			t0, t1 = math.Max(t0, 0), math.Min(t1, 1)
+			ok = t0 < t1
+		case p1 == p2:
+			// zero length segment lies inside the ellipse if its point does
+			t0, t1, ok = 0, 1, e.Contains(p1.X, p1.Y)
+		}
+
+		if !ok {
+			if part != nil {
+				parts = append(parts, part)
+				part = nil
+			}
+			continue
+		}
+
+		// the segment enters the ellipse unless it continues the current part
+		if part == nil || t0 > 0 {
+			if part != nil {
+				parts = append(parts, part)
+			}
+			part = plotter.XYs{lerpXY(p1, p2, t0)}
+		}
+		part = append(part, lerpXY(p1, p2, t1))
+
+		// the segment leaves the ellipse
+		if t1 < 1 {
+			parts = append(parts, part)
+			part = nil
+		}
+	}
+
+	if part != nil {
+		parts = append(parts, part)
+	}
+
+	return parts
+}
+
+// lerpXY returns the point p1 + t*(p2-p1).
+// The segment endpoints are returned exactly for t equal to 0 and 1.
+func lerpXY(p1, p2 plotter.XY, t float64) plotter.XY {
+	switch t {
+	case 0:
+		return p1
+	case 1:
+		return p2
+	}
+
+	return plotter.XY{X: p1.X + t*(p2.X-p1.X), Y: p1.Y + t*(p2.Y-p1.Y)}
+}
+
+// ClipRect returns the boundary of the ellipse clipped to the axis aligned rectangle [minX, minY, maxX, maxY].
+// The ellipse is approximated by the polygon whose vertices are size number of the ellipse boundary points
+// which is then clipped by the rectangle using Sutherland-Hodgman algorithm. The returned boundary contains
+// the rectangle edge segments wherever the ellipse extends beyond the rectangle.
+// It returns no points if the ellipse lies outside the rectangle.
+// It returns error if the rectangle is empty or if size is smaller than 3.
+//
+// For more information see: https://en.wikipedia.org/wiki/Sutherland%E2%80%93Hodgman_algorithm
+func (e *Ellipse) ClipRect(minX, minY, maxX, maxY float64, size int) (plotter.XYs, error) {
+	if !(minX < maxX) || !(minY < maxY) {
+		return nil, fmt.Errorf("Invalid rectangle: [%.2f, %.2f, %.2f, %.2f]", minX, minY, maxX, maxY)
+	}
+	if size < 3 {
+		return nil, fmt.Errorf("Invalid number of points: %d", size)
+	}
+
+	xys := e.boundary(size + 1)
+	// the last point closes the boundary
+	poly := xys[:size]
+
+	edges := []struct {
+		inside func(p plotter.XY) bool
+		cross  func(p, q plotter.XY) plotter.XY
+	}{
+		{
+			func(p plotter.XY) bool { return p.X >= minX },
+			func(p, q plotter.XY) plotter.XY { return plotter.XY{X: minX, Y: p.Y + (q.Y-p.Y)*(minX-p.X)/(q.X-p.X)} },
+		},
+		{
+			func(p plotter.XY) bool { return p.X <= maxX },
+			func(p, q plotter.XY) plotter.XY { return plotter.XY{X: maxX, Y: p.Y + (q.Y-p.Y)*(maxX-p.X)/(q.X-p.X)} },
+		},
+		{
+			func(p plotter.XY) bool { return p.Y >= minY },
+			func(p, q plotter.XY) plotter.XY { return plotter.XY{X: p.X + (q.X-p.X)*(minY-p.Y)/(q.Y-p.Y), Y: minY} },
+		},
+		{
+			func(p plotter.XY) bool { return p.Y <= maxY },
+			func(p, q plotter.XY) plotter.XY { return plotter.XY{X: p.X + (q.X-p.X)*(maxY-p.Y)/(q.Y-p.Y), Y: maxY} },
+		},
+	}
+
+	for _, edge := range edges {
+		var clipped plotter.XYs
+		for i, q := range poly {
+			p := poly[(i+len(poly)-1)%len(poly)]
+			switch pin, qin := edge.inside(p), edge.inside(q); {
+			case pin && qin:
+				clipped = append(clipped, q)
+			case pin:
+				clipped = append(clipped, edge.cross(p, q))
+			case qin:
+				clipped = append(clipped, edge.cross(p, q), q)
+			}
+		}
+		if len(clipped) == 0 {
+			return nil, nil
+		}
+		poly = clipped
+	}
+
+	return poly, nil
+}
@@ -0,0 +1,133 @@
+package ellipse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestIntersectLine(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 4}
+
+	// line through the center intersects the ellipse at its vertices
+	sin, cos := math.Sincos(ell.angle)
+	pts := ell.IntersectLine(plotter.XY{X: 1.0, Y: 2.0}, plotter.XY{X: 1.0 + cos, Y: 2.0 + sin})
+	assert.Len(pts, 2)
+	assert.InDelta(1.0-3*cos, pts[0].X, 1e-9)
+	assert.InDelta(2.0-3*sin, pts[0].Y, 1e-9)
+	assert.InDelta(1.0+3*cos, pts[1].X, 1e-9)
+	assert.InDelta(2.0+3*sin, pts[1].Y, 1e-9)
+
+	// arbitrary line
+	pts = ell.IntersectLine(plotter.XY{X: -5.0, Y: 1.0}, plotter.XY{X: 5.0, Y: 3.0})
+	assert.Len(pts, 2)
+	for _, p := range pts {
+		assert.InDelta(0, ell.Implicit(p.X, p.Y), 1e-9)
+	}
+	assert.True(pts[0].X < pts[1].X)
+
+	// tangent line
+	circle := &Ellipse{a: 1.0, b: 1.0}
+	pts = circle.IntersectLine(plotter.XY{X: -2.0, Y: 1.0}, plotter.XY{X: 2.0, Y: 1.0})
+	assert.Len(pts, 1)
+	assert.InDelta(0, pts[0].X, 1e-9)
+	assert.InDelta(1.0, pts[0].Y, 1e-9)
+
+	assert.Nil(circle.IntersectLine(plotter.XY{X: -2.0, Y: 2.0}, plotter.XY{X: 2.0, Y: 2.0}))
+	assert.Nil(circle.IntersectLine(plotter.XY{}, plotter.XY{}))
+}
+
+func TestClipPolyline(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 1.0, a: 2.0, b: 1.0}
+
+	// line crossing the ellipse
+	parts := ell.ClipPolyline(plotter.XYs{{X: -5.0, Y: 1.0}, {X: 5.0, Y: 1.0}})
+	assert.Len(parts, 1)
+	assert.Len(parts[0], 2)
+	for _, p := range parts[0] {
+		assert.InDelta(0, ell.Implicit(p.X, p.Y), 1e-9)
+	}
+	assert.InDelta(-1.0, parts[0][0].X, 1e-9)
+	assert.InDelta(3.0, parts[0][1].X, 1e-9)
+
+	// polyline entering, bending inside and leaving the ellipse twice
+	pts := plotter.XYs{{X: -3.0, Y: 1.0}, {X: 1.0, Y: 1.0}, {X: 1.0, Y: 3.0}, {X: 1.0, Y: -3.0}, {X: 5.0, Y: -3.0}}
+	parts = ell.ClipPolyline(pts)
+	assert.Len(parts, 2)
+	assert.Len(parts[0], 3)
+	assert.InDelta(-1.0, parts[0][0].X, 1e-9)
+	assert.Equal(pts[1], parts[0][1])
+	assert.InDelta(1.0, parts[0][2].X, 1e-9)
+	assert.InDelta(2.0, parts[0][2].Y, 1e-9)
+	assert.Len(parts[1], 2)
+	assert.InDelta(2.0, parts[1][0].Y, 1e-9)
+	assert.InDelta(0, parts[1][1].Y, 1e-9)
+
+	// polyline inside the ellipse is returned unchanged
+	pts = plotter.XYs{{X: 0.5, Y: 1.0}, {X: 1.0, Y: 1.5}, {X: 1.5, Y: 1.0}}
+	parts = ell.ClipPolyline(pts)
+	assert.Len(parts, 1)
+	assert.Equal(pts, parts[0])
+
+	// polyline outside the ellipse
+	assert.Nil(ell.ClipPolyline(plotter.XYs{{X: -5.0, Y: 5.0}, {X: 5.0, Y: 5.0}}))
+	assert.Nil(ell.ClipPolyline(plotter.XYs{{X: 1.0, Y: 1.0}}))
+}
+
+func TestClipRect(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 1.0, a: 3.0, b: 1.0, angle: math.Pi / 6}
+
+	// rectangle containing the whole ellipse leaves its boundary unchanged
+	pts, err := ell.ClipRect(-5.0, -5.0, 5.0, 5.0, 100)
+	assert.NoError(err)
+	_, points, err := ell.LinePoints(101)
+	assert.NoError(err)
+	assert.Equal(points.XYs[:100], pts)
+
+	// ellipse partly outside the rectangle
+	pts, err = ell.ClipRect(0, 0, 3.0, 3.0, 100)
+	assert.NoError(err)
+	assert.NotEmpty(pts)
+
+	onEdgeX, onEdgeY := 0, 0
+	for _, p := range pts {
+		assert.True(p.X >= 0 && p.X <= 3.0 && p.Y >= 0 && p.Y <= 3.0)
+		if p.X == 0 || p.X == 3.0 {
+			onEdgeX++
+		}
+		if p.Y == 0 || p.Y == 3.0 {
+			onEdgeY++
+		}
+	}
+	// the ellipse leaves the rectangle through its left, right and bottom edge
+	assert.True(onEdgeX >= 4)
+	assert.True(onEdgeY >= 2)
+
+	// ellipse outside of the rectangle
+	pts, err = ell.ClipRect(10.0, 10.0, 12.0, 12.0, 100)
+	assert.NoError(err)
+	assert.Empty(pts)
+
+	testCases := []struct {
+		rect [4]float64
+		size int
+	}{
+		{[4]float64{1.0, 0, 0, 1.0}, 100},
+		{[4]float64{0, 1.0, 1.0, 1.0}, 100},
+		{[4]float64{0, 0, 1.0, 1.0}, 2},
+	}
+
+	for _, tc := range testCases {
+		pts, err := ell.ClipRect(tc.rect[0], tc.rect[1], tc.rect[2], tc.rect[3], tc.size)
+		assert.Error(err)
+		assert.Nil(pts)
+	}
+}
@@ -0,0 +1,417 @@
+package ellipse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/plot/plotter"
+)
+
+// ErrDegenerate is returned when the ellipse degenerates into a sliver which is approximately a line segment.
+var ErrDegenerate = errors.New("Degenerate ellipse")
+
+// errRankDeficient is returned when the data covariance is not positive definite.
+var errRankDeficient = errors.New("Rank deficient data covariance")
+
+// ConfidenceModel is a model of 2D Gaussian data which creates confidence ellipses
+// of arbitrary confidence without recomputing the Principal Components of the data.
+type ConfidenceModel struct {
+	x       float64
+	y       float64
+	eigVals []float64
+	angle   float64
+}
+
+// NewConfidenceModel creates new ConfidenceModel from data.
+// The data is assumed to be of the Normal (a.k.a. Gaussian) distribution.
+// It returns error if principal components could not be calculated from the supplied data
+// or if any of the data covariance eigenvalues is not positive e.g. when the data is rank deficient.
+func NewConfidenceModel(data mat.Matrix) (*ConfidenceModel, error) {
+	eigVals, eigVecs, mean, err := PrincipalAxes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// numerical errors can yield tiny negative eigenvalues which would make the ellipse axes NaN
+	if !(eigVals[1] > 0) {
+		return nil, fmt.Errorf("Invalid data covariance eigenvalues: %v: %w", eigVals, errRankDeficient)
+	}
+
+	// Calculate Ellipse rotation angle from the largest eigenvector i.e. the first column of eigVecs
+	// pc.VectorsTo returns eigenvalues/vectors in descending order
+	// The sign of the eigenvector is arbitrary, so the angle is normalized into <0, pi) interval
+	angle := normalizeAngle(math.Atan2(eigVecs.At(1, 0), eigVecs.At(0, 0)))
+
+	return &ConfidenceModel{
+		x:       mean[0],
+		y:       mean[1],
+		eigVals: eigVals,
+		angle:   angle,
+	}, nil
+}
+
+// PrincipalAxes returns the principal components of the data stored in the first two columns of data.
+// It returns the eigenvalues of the data covariance in descending order, the matrix whose columns
+// are the corresponding eigenvectors and the data mean.
+// It returns error if data has less than 2 columns or if principal components could not be calculated.
+func PrincipalAxes(data mat.Matrix) (eigVals []float64, eigVecs *mat.Dense, mean [2]float64, err error) {
+	rows, cols := data.Dims()
+	if cols < 2 {
+		return nil, nil, mean, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+
+	xy := mat.NewDense(rows, 2, nil)
+	xy.Copy(data)
+
+	// calculate x and y mean values
+	vals := make([]float64, rows)
+	mean[0] = stat.Mean(mat.Col(vals, 0, xy), nil)
+	mean[1] = stat.Mean(mat.Col(vals, 1, xy), nil)
+
+	// calculate data eigenvectors and eigenvalues
+	var pc stat.PC
+	ok := pc.PrincipalComponents(xy, nil)
+	if !ok {
+		return nil, nil, mean, fmt.Errorf("Could not determine Principal Components")
+	}
+	eigVals = pc.VarsTo(nil)
+	eigVecs = &mat.Dense{}
+	pc.VectorsTo(eigVecs)
+
+	return eigVals, eigVecs, mean, nil
+}
+
+// NewWithDataConfidenceT creates new Ellipse from data with origin being data mean and confidence probability
+// using Hotelling's T-squared distribution instead of Chi-squared distribution to scale the ellipse axes.
+// The axes are scaled by 2*(n-1)/(n-2) * F(confidence; 2, n-2) quantile of F-distribution, where n is the number of data rows.
+// With few data points the data covariance is estimated with large uncertainty and Chi-squared based
+// confidence ellipse understates the confidence region; T-squared based ellipse accounts for it and
+// converges to Chi-squared based ellipse as n grows.
+// It returns error if confidence is not in (0,1) interval, if data has less than 3 rows
+// or if principal components could not be calculated from the supplied data.
+//
+// For more information see: https://en.wikipedia.org/wiki/Hotelling%27s_T-squared_distribution
+func NewWithDataConfidenceT(data mat.Matrix, confidence float64) (*Ellipse, error) {
+	if err := checkConfidence(confidence); err != nil {
+		return nil, err
+	}
+
+	n, _ := data.Dims()
+	if n < 3 {
+		return nil, fmt.Errorf("Insufficient number of data rows: %d", n)
+	}
+
+	f := distuv.F{D1: 2, D2: float64(n - 2)}
+	quantile := func(p float64) float64 {
+		return 2 * float64(n-1) / float64(n-2) * f.Quantile(p)
+	}
+
+	return NewWithDataConfidenceFunc(data, confidence, quantile)
+}
+
+// NewWithDataConfidenceFunc creates new Ellipse from data with origin being data mean and confidence probability
+// whose axes are scaled by the square root of quantile(confidence). The quantile function maps the confidence
+// to the squared Mahalanobis distance of the ellipse boundary from the data mean.
+// If quantile is nil, the quantile function of Chi-squared distribution with 2 degrees of freedom is used,
+// which is what NewWithDataConfidence does.
+// It returns error if confidence is not in (0,1) interval, if the quantile is not positive
+// or if principal components could not be calculated from the supplied data.
+func NewWithDataConfidenceFunc(data mat.Matrix, confidence float64, quantile func(p float64) float64) (*Ellipse, error) {
+	if err := checkConfidence(confidence); err != nil {
+		return nil, err
+	}
+
+	if quantile == nil {
+		quantile = distuv.ChiSquared{K: 2}.Quantile
+	}
+
+	scale := quantile(confidence)
+	if !(scale > 0) {
+		return nil, fmt.Errorf("Invalid confidence quantile: %v", scale)
+	}
+
+	m, err := NewConfidenceModel(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.ellipse(math.Sqrt(scale)), nil
+}
+
+// NewWithDataConfidenceStrict creates new Ellipse from data with origin being data mean and confidence probability
+// just like NewWithDataConfidence does, but it reports the ellipses which are degenerate with tol tolerance.
+// The degenerate ellipse is returned along with error which wraps ErrDegenerate, so the callers can decide
+// whether to use it or not; such error can be checked with errors.Is.
+// It returns error if confidence is not in (0,1) interval or if principal components could not be calculated from the supplied data.
+func NewWithDataConfidenceStrict(data mat.Matrix, confidence, tol float64) (*Ellipse, error) {
+	radius, err := ConfidenceToRadius(confidence)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := NewConfidenceModel(data)
+	if err != nil {
+		return nil, err
+	}
+
+	e := m.ellipse(radius)
+	if e.IsDegenerate(tol) {
+		return e, fmt.Errorf("Invalid data axes ratio: %v: %w", e.b/e.a, ErrDegenerate)
+	}
+
+	return e, nil
+}
+
+// NewWithDataConfidenceSampled creates new Ellipse from at most maxRows randomly sampled data rows
+// with origin being the mean of the sampled data and confidence probability.
+// The rows are sampled without replacement using src random source. If src is nil, a source seeded with a fixed seed is used.
+// If data has no more than maxRows rows, all of the data is used.
+// Computing the principal components of the sampled data is faster than of the whole data set,
+// however the sampled data mean and covariance are only estimates of the full data ones whose
+// standard error decreases with the square root of maxRows, so the sampled ellipse only approximates the full one.
+// It returns error if maxRows is smaller than 2, if confidence is not in (0,1) interval
+// or if principal components could not be calculated from the sampled data.
+func NewWithDataConfidenceSampled(data mat.Matrix, confidence float64, maxRows int, src rand.Source) (*Ellipse, error) {
+	if maxRows < 2 {
+		return nil, fmt.Errorf("Invalid number of sampled rows: %d", maxRows)
+	}
+
+	radius, err := ConfidenceToRadius(confidence)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, cols := data.Dims()
+	if cols < 2 {
+		return nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+
+	sample := data
+	if rows > maxRows {
+		rnd := newRand(src)
+		idx := make([]int, rows)
+		for i := range idx {
+			idx[i] = i
+		}
+
+		// partial Fisher-Yates shuffle selects maxRows distinct rows
+		xy := mat.NewDense(maxRows, 2, nil)
+		for i := 0; i < maxRows; i++ {
+			j := i + rnd.Intn(rows-i)
+			idx[i], idx[j] = idx[j], idx[i]
+			xy.Set(i, 0, data.At(idx[i], 0))
+			xy.Set(i, 1, data.At(idx[i], 1))
+		}
+		sample = xy
+	}
+
+	m, err := NewConfidenceModel(sample)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.ellipse(radius), nil
+}
+
+// NewWithDataConfidencePair creates new Ellipse from the i-th and j-th column of data
+// with origin being the mean of the columns and confidence probability.
+// The i-th column is used as X coordinates and the j-th column as Y coordinates of the data points.
+// It returns error if either of the column indices is out of data bounds, if i and j are the same,
+// if confidence is not in (0,1) interval or if principal components could not be calculated from the supplied data.
+func NewWithDataConfidencePair(data mat.Matrix, i, j int, confidence float64) (*Ellipse, error) {
+	rows, cols := data.Dims()
+	if i < 0 || i >= cols || j < 0 || j >= cols || i == j {
+		return nil, fmt.Errorf("Invalid data column pair: (%d, %d)", i, j)
+	}
+
+	radius, err := ConfidenceToRadius(confidence)
+	if err != nil {
+		return nil, err
+	}
+
+	xy := mat.NewDense(rows, 2, nil)
+	for r := 0; r < rows; r++ {
+		xy.Set(r, 0, data.At(r, i))
+		xy.Set(r, 1, data.At(r, j))
+	}
+
+	m, err := NewConfidenceModel(xy)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.ellipse(radius), nil
+}
+
+// PairwiseConfidence creates confidence Ellipses for every pair of data columns with confidence probability.
+// It returns NxN matrix of ellipses, where N is the number of data columns, whose element [i][j]
+// for i < j is the confidence ellipse of the i-th (X coordinates) and the j-th (Y coordinates) data column.
+// The elements on and below the diagonal are nil.
+// The data covariance is computed only once and shared by all the column pairs.
+// It returns error if data has less than 2 columns, if confidence is not in (0,1) interval
+// or if the covariance of any of the column pairs is not positive definite.
+func PairwiseConfidence(data mat.Matrix, confidence float64) ([][]*Ellipse, error) {
+	rows, cols := data.Dims()
+	if cols < 2 {
+		return nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+
+	if _, err := ConfidenceToRadius(confidence); err != nil {
+		return nil, err
+	}
+
+	means := make([]float64, cols)
+	vals := make([]float64, rows)
+	for i := range means {
+		means[i] = stat.Mean(mat.Col(vals, i, data), nil)
+	}
+
+	cov := mat.NewSymDense(cols, nil)
+	stat.CovarianceMatrix(cov, data, nil)
+
+	ellipses := make([][]*Ellipse, cols)
+	for i := range ellipses {
+		ellipses[i] = make([]*Ellipse, cols)
+		for j := i + 1; j < cols; j++ {
+			pair := mat.NewSymDense(2, []float64{
+				cov.At(i, i), cov.At(i, j),
+				cov.At(j, i), cov.At(j, j),
+			})
+
+			e, err := NewFromCovariance(means[i], means[j], pair, confidence)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid data column pair (%d, %d): %w", i, j, err)
+			}
+			ellipses[i][j] = e
+		}
+	}
+
+	return ellipses, nil
+}
+
+// NewDiffConfidence creates new confidence Ellipse with confidence probability of the difference of the means
+// of the points stored in the first two columns of a and b i.e. mean(a) - mean(b).
+// The ellipse is centered at the difference of the means and its covariance is cov/na + cov/nb,
+// where na and nb are the numbers of the rows of a and b and cov is the pooled covariance of a and b:
+// ((na-1)*cov(a) + (nb-1)*cov(b)) / (na+nb-2).
+// The confidence region contains the origin if the means do not differ at the given confidence level.
+// It returns error if either a or b has less than 2 columns or no rows, if a and b have less than 3 rows in total,
+// if confidence is not in (0,1) interval or if the pooled covariance is not positive definite.
+//
+// For more information see: https://en.wikipedia.org/wiki/Pooled_variance
+func NewDiffConfidence(a, b mat.Matrix, confidence float64) (*Ellipse, error) {
+	na, cols := a.Dims()
+	if cols < 2 {
+		return nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+
+	nb, cols := b.Dims()
+	if cols < 2 {
+		return nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+
+	if na < 1 || nb < 1 || na+nb < 3 {
+		return nil, fmt.Errorf("Insufficient number of points: (%d, %d)", na, nb)
+	}
+
+	// scatter returns the mean of the data points and the sums of squared deviations from it
+	scatter := func(data mat.Matrix, n int) (mx, my, sxx, sxy, syy float64) {
+		for i := 0; i < n; i++ {
+			mx += data.At(i, 0)
+			my += data.At(i, 1)
+		}
+		mx, my = mx/float64(n), my/float64(n)
+
+		for i := 0; i < n; i++ {
+			dx, dy := data.At(i, 0)-mx, data.At(i, 1)-my
+			sxx += dx * dx
+			sxy += dx * dy
+			syy += dy * dy
+		}
+
+		return mx, my, sxx, sxy, syy
+	}
+
+	ax, ay, axx, axy, ayy := scatter(a, na)
+	bx, by, bxx, bxy, byy := scatter(b, nb)
+
+	// pooled covariance scaled by 1/na + 1/nb
+	s := (1/float64(na) + 1/float64(nb)) / float64(na+nb-2)
+	cov := mat.NewSymDense(2, []float64{
+		s * (axx + bxx), s * (axy + bxy),
+		s * (axy + bxy), s * (ayy + byy),
+	})
+
+	return NewFromCovariance(ax-bx, ay-by, cov, confidence)
+}
+
+// WindowedConfidence creates confidence Ellipses with confidence probability from windows of data rows.
+// The window of window consecutive data rows is slid across the data one row at a time
+// and a confidence ellipse is created for each window position, so the returned slice
+// contains rows-window+1 ellipses ordered by the first row of the window.
+// It returns error if window is smaller than 2 or exceeds the number of data rows,
+// if confidence is not in (0,1) interval or if principal components of any of the windows could not be calculated.
+func WindowedConfidence(data mat.Matrix, window int, confidence float64) ([]*Ellipse, error) {
+	rows, cols := data.Dims()
+	if window < 2 || window > rows {
+		return nil, fmt.Errorf("Invalid window size: %d", window)
+	}
+
+	radius, err := ConfidenceToRadius(confidence)
+	if err != nil {
+		return nil, err
+	}
+
+	xy := mat.DenseCopyOf(data)
+
+	ellipses := make([]*Ellipse, rows-window+1)
+	for i := range ellipses {
+		m, err := NewConfidenceModel(xy.Slice(i, i+window, 0, cols))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid data window at row %d: %w", i, err)
+		}
+		ellipses[i] = m.ellipse(radius)
+	}
+
+	return ellipses, nil
+}
+
+// Ellipse creates new confidence Ellipse which contains confidence probability mass of the data distribution.
+// It returns error if confidence is not in (0,1) interval.
+func (m *ConfidenceModel) Ellipse(confidence float64) (*Ellipse, error) {
+	radius, err := ConfidenceToRadius(confidence)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.ellipse(radius), nil
+}
+
+// LinePoints returns both plotter.Line and plotter.Scatter which can be used to plot the confidence Ellipse
+// which contains confidence probability mass of the data distribution.
+// It returns error if confidence is not in (0,1) interval or if at least one of the ellipse data points contains a NaN or Infinity.
+func (m *ConfidenceModel) LinePoints(confidence float64, size int) (*plotter.Line, *plotter.Scatter, error) {
+	e, err := m.Ellipse(confidence)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return e.LinePointsContext(context.Background(), size)
+}
+
+// ellipse creates new Ellipse whose boundary is radius Mahalanobis distance away from the data mean.
+func (m *ConfidenceModel) ellipse(radius float64) *Ellipse {
+	scale := radius * radius
+
+	// pc.VarsTo returns eigenvalues in descending order
+	a := math.Sqrt(scale * m.eigVals[0])
+	b := math.Sqrt(scale * m.eigVals[1])
+
+	return &Ellipse{x: m.x, y: m.y, a: a, b: b, angle: m.angle, scale: scale}
+}
@@ -0,0 +1,516 @@
+package ellipse
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestConfidenceModel(t *testing.T) {
+	assert := assert.New(t)
+
+	data := gaussData(100, 1.0, 2.0, 1)
+
+	m, err := NewConfidenceModel(data)
+	assert.NoError(err)
+
+	for _, c := range []float64{0.05, 0.5, 0.95, 0.99} {
+		ell, err := m.Ellipse(c)
+		assert.NoError(err)
+
+		exp, err := NewWithDataConfidence(data, c)
+		assert.NoError(err)
+		assert.Equal(exp, ell)
+
+		line, points, err := m.LinePoints(c, 10)
+		assert.NoError(err)
+		assert.NotNil(line)
+		assert.Equal(10, points.Len())
+
+		_, expPoints, err := exp.LinePoints(10)
+		assert.NoError(err)
+		assert.Equal(expPoints.XYs, points.XYs)
+	}
+
+	for _, c := range []float64{0, -1.0, 2.0} {
+		ell, err := m.Ellipse(c)
+		assert.Error(err)
+		assert.Nil(ell)
+
+		line, points, err := m.LinePoints(c, 10)
+		assert.Error(err)
+		assert.Nil(line)
+		assert.Nil(points)
+	}
+}
+
+func TestNewWithDataConfidenceT(t *testing.T) {
+	assert := assert.New(t)
+
+	// small sample T-squared ellipse is larger than Chi-squared one
+	data := gaussData(10, 1.0, 2.0, 1)
+	ell, err := NewWithDataConfidenceT(data, 0.95)
+	assert.NoError(err)
+	chi, err := NewWithDataConfidence(data, 0.95)
+	assert.NoError(err)
+	assert.True(ell.a > chi.a)
+	assert.True(ell.b > chi.b)
+	assert.Equal(chi.x, ell.x)
+	assert.Equal(chi.y, ell.y)
+	assert.Equal(chi.angle, ell.angle)
+
+	// large sample T-squared ellipse converges to Chi-squared one
+	data = gaussData(100000, 1.0, 2.0, 1)
+	ell, err = NewWithDataConfidenceT(data, 0.95)
+	assert.NoError(err)
+	chi, err = NewWithDataConfidence(data, 0.95)
+	assert.NoError(err)
+	assert.InEpsilon(chi.a, ell.a, 1e-3)
+	assert.InEpsilon(chi.b, ell.b, 1e-3)
+
+	testCases := []struct {
+		m *mat.Dense
+		c float64
+	}{
+		{data, 0},
+		{data, 1.5},
+		{mat.NewDense(2, 2, []float64{1.0, 2.0, 3.0, 4.0}), 0.95},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewWithDataConfidenceT(tc.m, tc.c)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestNewWithDataConfidenceFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	data := gaussData(100, 1.0, 2.0, 1)
+	chi2 := distuv.ChiSquared{K: 2}
+
+	for _, quantile := range []func(float64) float64{chi2.Quantile, nil} {
+		ell, err := NewWithDataConfidenceFunc(data, 0.95, quantile)
+		assert.NoError(err)
+
+		exp, err := NewWithDataConfidence(data, 0.95)
+		assert.NoError(err)
+		assert.Equal(exp.x, ell.x)
+		assert.Equal(exp.y, ell.y)
+		assert.Equal(exp.angle, ell.angle)
+		assert.InDelta(exp.a, ell.a, 1e-12)
+		assert.InDelta(exp.b, ell.b, 1e-12)
+	}
+
+	// custom quantile scales the ellipse axes
+	unit, err := NewWithDataConfidenceFunc(data, 0.95, func(float64) float64 { return 1.0 })
+	assert.NoError(err)
+	double, err := NewWithDataConfidenceFunc(data, 0.95, func(float64) float64 { return 4.0 })
+	assert.NoError(err)
+	assert.InDelta(2*unit.a, double.a, 1e-12)
+	assert.InDelta(2*unit.b, double.b, 1e-12)
+
+	testCases := []struct {
+		c        float64
+		quantile func(float64) float64
+	}{
+		{0, nil},
+		{1.5, nil},
+		{0.95, func(float64) float64 { return 0 }},
+		{0.95, func(float64) float64 { return math.NaN() }},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewWithDataConfidenceFunc(data, tc.c, tc.quantile)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestPrincipalAxes(t *testing.T) {
+	assert := assert.New(t)
+
+	// gaussData has variance 18 along [1,1] direction and 2 along [1,-1] direction
+	data := gaussData(100000, 1.0, 2.0, 1)
+
+	eigVals, eigVecs, mean, err := PrincipalAxes(data)
+	assert.NoError(err)
+	assert.Len(eigVals, 2)
+	assert.InEpsilon(18.0, eigVals[0], 0.05)
+	assert.InEpsilon(2.0, eigVals[1], 0.05)
+	assert.InDelta(1.0, mean[0], 0.05)
+	assert.InDelta(2.0, mean[1], 0.05)
+
+	r, c := eigVecs.Dims()
+	assert.Equal(2, r)
+	assert.Equal(2, c)
+	// principal direction is parallel to [1,1]
+	assert.InDelta(1.0, math.Abs(eigVecs.At(0, 0)+eigVecs.At(1, 0))/math.Sqrt2, 1e-2)
+
+	// only the first two columns are used
+	wide := mat.NewDense(100, 3, nil)
+	wide.Slice(0, 100, 0, 2).(*mat.Dense).Copy(data.Slice(0, 100, 0, 2))
+	for i := 0; i < 100; i++ {
+		wide.Set(i, 2, float64(i))
+	}
+	wideVals, _, wideMean, err := PrincipalAxes(wide)
+	assert.NoError(err)
+	expVals, _, expMean, err := PrincipalAxes(data.Slice(0, 100, 0, 2))
+	assert.NoError(err)
+	assert.InDeltaSlice(expVals, wideVals, 1e-9)
+	assert.Equal(expMean, wideMean)
+
+	_, _, _, err = PrincipalAxes(mat.NewDense(3, 1, nil))
+	assert.Error(err)
+}
+
+func TestNewWithDataConfidencePair(t *testing.T) {
+	assert := assert.New(t)
+
+	xy := gaussData(100, 1.0, 2.0, 1)
+	data := mat.NewDense(100, 3, nil)
+	for r := 0; r < 100; r++ {
+		data.Set(r, 0, xy.At(r, 0))
+		data.Set(r, 1, xy.At(r, 1))
+		data.Set(r, 2, xy.At(r, 1)-xy.At(r, 0))
+	}
+
+	exp, err := NewWithDataConfidence(xy, 0.95)
+	assert.NoError(err)
+	ell, err := NewWithDataConfidencePair(data, 0, 1, 0.95)
+	assert.NoError(err)
+	assert.Equal(exp, ell)
+
+	// swapped columns swap the ellipse center coordinates
+	ell, err = NewWithDataConfidencePair(data, 1, 0, 0.95)
+	assert.NoError(err)
+	assert.InDelta(exp.y, ell.x, 1e-9)
+	assert.InDelta(exp.x, ell.y, 1e-9)
+	assert.InDelta(exp.a, ell.a, 1e-9)
+	assert.InDelta(exp.b, ell.b, 1e-9)
+	// and mirror the ellipse around the diagonal
+	assert.InDelta(normalizeAngle(math.Pi/2-exp.angle), ell.angle, 1e-9)
+
+	ell, err = NewWithDataConfidencePair(data, 0, 2, 0.95)
+	assert.NoError(err)
+	assert.InDelta(exp.x, ell.x, 1e-9)
+	assert.InDelta(exp.y-exp.x, ell.y, 1e-9)
+
+	testCases := []struct {
+		i int
+		j int
+		c float64
+	}{
+		{0, 0, 0.95},
+		{-1, 1, 0.95},
+		{0, 3, 0.95},
+		{0, 1, 0},
+		{0, 1, 1.5},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewWithDataConfidencePair(data, tc.i, tc.j, tc.c)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestPairwiseConfidence(t *testing.T) {
+	assert := assert.New(t)
+
+	xy := gaussData(100, 1.0, 2.0, 1)
+	data := mat.NewDense(100, 3, nil)
+	for r := 0; r < 100; r++ {
+		data.Set(r, 0, xy.At(r, 0))
+		data.Set(r, 1, xy.At(r, 1))
+		data.Set(r, 2, xy.At(r, 1)-xy.At(r, 0))
+	}
+
+	ellipses, err := PairwiseConfidence(data, 0.95)
+	assert.NoError(err)
+	assert.Len(ellipses, 3)
+
+	count := 0
+	for i := range ellipses {
+		assert.Len(ellipses[i], 3)
+		for j := range ellipses[i] {
+			if j <= i {
+				assert.Nil(ellipses[i][j])
+				continue
+			}
+			assert.NotNil(ellipses[i][j])
+			count++
+
+			exp, err := NewWithDataConfidencePair(data, i, j, 0.95)
+			assert.NoError(err)
+			assert.InDelta(exp.x, ellipses[i][j].x, 1e-9)
+			assert.InDelta(exp.y, ellipses[i][j].y, 1e-9)
+			assert.InDelta(exp.a, ellipses[i][j].a, 1e-9)
+			assert.InDelta(exp.b, ellipses[i][j].b, 1e-9)
+		}
+	}
+	assert.Equal(3, count)
+
+	testCases := []struct {
+		m *mat.Dense
+		c float64
+	}{
+		{data, 0},
+		{data, 1.5},
+		{mat.NewDense(2, 1, []float64{1.0, 2.0}), 0.95},
+	}
+
+	for _, tc := range testCases {
+		ellipses, err := PairwiseConfidence(tc.m, tc.c)
+		assert.Error(err)
+		assert.Nil(ellipses)
+	}
+
+	// the error of the column pair wraps the covariance error
+	for r := 0; r < 100; r++ {
+		data.Set(r, 2, 2*data.At(r, 0))
+	}
+	ellipses, err = PairwiseConfidence(data, 0.95)
+	assert.Error(err)
+	assert.Nil(ellipses)
+	assert.NotNil(errors.Unwrap(err))
+}
+
+func TestNewWithDataConfidenceStrict(t *testing.T) {
+	assert := assert.New(t)
+
+	data := gaussData(100, 1.0, 2.0, 1)
+	ell, err := NewWithDataConfidenceStrict(data, 0.95, 1e-3)
+	assert.NoError(err)
+	exp, err := NewWithDataConfidence(data, 0.95)
+	assert.NoError(err)
+	assert.Equal(exp, ell)
+
+	// nearly collinear data
+	collinear := mat.NewDense(100, 2, nil)
+	for r := 0; r < 100; r++ {
+		x := data.At(r, 0)
+		collinear.Set(r, 0, x)
+		collinear.Set(r, 1, 2*x+1e-6*data.At(r, 1))
+	}
+
+	ell, err = NewWithDataConfidenceStrict(collinear, 0.95, 1e-3)
+	assert.Error(err)
+	assert.True(errors.Is(err, ErrDegenerate))
+	assert.NotNil(ell)
+	assert.True(ell.IsDegenerate(1e-3))
+
+	for _, c := range []float64{0, 1.5} {
+		ell, err := NewWithDataConfidenceStrict(data, c, 1e-3)
+		assert.Error(err)
+		assert.False(errors.Is(err, ErrDegenerate))
+		assert.Nil(ell)
+	}
+}
+
+func TestRankDeficientData(t *testing.T) {
+	assert := assert.New(t)
+
+	// constant Y coordinates make the data covariance singular
+	data := mat.NewDense(4, 2, []float64{1.0, 2.0, 3.0, 2.0, 5.0, 2.0, 7.0, 2.0})
+
+	m, err := NewConfidenceModel(data)
+	assert.Error(err)
+	assert.Nil(m)
+
+	ell, err := NewWithDataConfidenceFunc(data, 0.95, nil)
+	assert.Error(err)
+	assert.Nil(ell)
+
+	ell, err = NewWithDataConfidenceT(data, 0.95)
+	assert.Error(err)
+	assert.Nil(ell)
+
+	ell, err = NewWithDataConfidencePair(data, 1, 0, 0.95)
+	assert.Error(err)
+	assert.Nil(ell)
+}
+
+func TestConfidenceModelAngle(t *testing.T) {
+	assert := assert.New(t)
+
+	// data with diagonal covariance whose major axis is along X axis
+	data := mat.NewDense(4, 2, []float64{3.0, 0, -3.0, 0, 0, 1.0, 0, -1.0})
+	ell, err := NewWithDataConfidence(data, 0.95)
+	assert.NoError(err)
+	assert.InDelta(0, ell.angle, 1e-12)
+
+	// data with diagonal covariance whose major axis is along Y axis
+	data = mat.NewDense(4, 2, []float64{1.0, 0, -1.0, 0, 0, 3.0, 0, -3.0})
+	ell, err = NewWithDataConfidence(data, 0.95)
+	assert.NoError(err)
+	assert.InDelta(math.Pi/2, ell.angle, 1e-12)
+
+	// data whose major axis is along [1,1] direction
+	ell, err = NewWithDataConfidence(gaussData(100000, 1.0, 2.0, 1), 0.95)
+	assert.NoError(err)
+	assert.InDelta(math.Pi/4, ell.angle, 1e-2)
+
+	// the angle agrees with the ellipse created from the data covariance
+	for _, seed := range []uint64{1, 2, 3} {
+		data := gaussData(100, 1.0, 2.0, seed)
+		ell, err := NewWithDataConfidence(data, 0.95)
+		assert.NoError(err)
+		assert.True(ell.angle >= 0 && ell.angle < math.Pi)
+
+		cov := mat.NewSymDense(2, nil)
+		stat.CovarianceMatrix(cov, data, nil)
+		exp, err := NewFromCovariance(ell.x, ell.y, cov, 0.95)
+		assert.NoError(err)
+		assert.True(exp.ApproxEqual(ell, 1e-9))
+	}
+}
+
+func TestWindowedConfidence(t *testing.T) {
+	assert := assert.New(t)
+
+	rows, window := 50, 10
+	data := gaussData(rows, 1.0, 2.0, 1)
+
+	ellipses, err := WindowedConfidence(data, window, 0.95)
+	assert.NoError(err)
+	assert.Len(ellipses, rows-window+1)
+
+	for _, i := range []int{0, rows - window} {
+		exp, err := NewWithDataConfidence(data.Slice(i, i+window, 0, 2), 0.95)
+		assert.NoError(err)
+		assert.True(exp.ApproxEqual(ellipses[i], 1e-9))
+	}
+
+	// window spanning all the data creates a single ellipse
+	ellipses, err = WindowedConfidence(data, rows, 0.95)
+	assert.NoError(err)
+	assert.Len(ellipses, 1)
+
+	testCases := []struct {
+		m      *mat.Dense
+		window int
+		c      float64
+	}{
+		{data, rows + 1, 0.95},
+		{data, 1, 0.95},
+		{data, 0, 0.95},
+		{data, window, 0},
+		{data, window, 1.5},
+		{mat.NewDense(3, 2, []float64{1.0, 1.0, 1.0, 1.0, 2.0, 2.0}), 2, 0.95},
+	}
+
+	for _, tc := range testCases {
+		ellipses, err := WindowedConfidence(tc.m, tc.window, tc.c)
+		assert.Error(err)
+		assert.Nil(ellipses)
+	}
+}
+
+func TestNewWithDataConfidenceSampled(t *testing.T) {
+	assert := assert.New(t)
+
+	data := gaussData(20000, 1.0, 2.0, 1)
+
+	full, err := NewWithDataConfidence(data, 0.95)
+	assert.NoError(err)
+
+	sampled, err := NewWithDataConfidenceSampled(data, 0.95, 2000, rand.NewSource(2))
+	assert.NoError(err)
+	assert.InDelta(full.x, sampled.x, 0.2)
+	assert.InDelta(full.y, sampled.y, 0.2)
+	assert.InDelta(1.0, sampled.a/full.a, 0.05)
+	assert.InDelta(1.0, sampled.b/full.b, 0.05)
+	assert.InDelta(full.angle, sampled.angle, 0.05)
+
+	// the same source yields the same sample
+	again, err := NewWithDataConfidenceSampled(data, 0.95, 2000, rand.NewSource(2))
+	assert.NoError(err)
+	assert.Equal(sampled, again)
+
+	// all the data is used if it has no more than maxRows rows
+	all, err := NewWithDataConfidenceSampled(data, 0.95, 20000, nil)
+	assert.NoError(err)
+	assert.Equal(full, all)
+
+	testCases := []struct {
+		m       *mat.Dense
+		c       float64
+		maxRows int
+	}{
+		{data, 0.95, 1},
+		{data, 0, 100},
+		{data, 1.0, 100},
+		{mat.NewDense(3, 1, []float64{1.0, 2.0, 3.0}), 0.95, 100},
+		{mat.NewDense(3, 2, []float64{1.0, 1.0, 1.0, 1.0, 1.0, 1.0}), 0.95, 100},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewWithDataConfidenceSampled(tc.m, tc.c, tc.maxRows, nil)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestNewDiffConfidence(t *testing.T) {
+	assert := assert.New(t)
+
+	a := gaussData(100, 1.0, 2.0, 1)
+
+	// identical data sets have the same mean
+	ell, err := NewDiffConfidence(a, a, 0.95)
+	assert.NoError(err)
+	assert.InDelta(0, ell.x, 1e-12)
+	assert.InDelta(0, ell.y, 1e-12)
+	assert.True(ell.Contains(0, 0))
+
+	// the pooled covariance of identical data sets is the data covariance
+	cov := mat.NewSymDense(2, nil)
+	stat.CovarianceMatrix(cov, a, nil)
+	diffCov, err := ell.ImpliedCovariance(0.95)
+	assert.NoError(err)
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			assert.InDelta(cov.At(i, j)*2/100, diffCov.At(i, j), 1e-9)
+		}
+	}
+
+	// distant data sets differ significantly
+	b := gaussData(50, 6.0, -3.0, 2)
+	ell, err = NewDiffConfidence(a, b, 0.95)
+	assert.NoError(err)
+	assert.InDelta(-5.0, ell.x, 1.5)
+	assert.InDelta(5.0, ell.y, 1.5)
+	assert.False(ell.Contains(0, 0))
+
+	// a single point is compared against the data set
+	ell, err = NewDiffConfidence(a, mat.NewDense(1, 2, []float64{1.0, 2.0}), 0.95)
+	assert.NoError(err)
+	assert.True(ell.Contains(0, 0))
+
+	testCases := []struct {
+		a *mat.Dense
+		b *mat.Dense
+		c float64
+	}{
+		{mat.NewDense(3, 1, []float64{1.0, 2.0, 3.0}), a, 0.95},
+		{a, mat.NewDense(3, 1, []float64{1.0, 2.0, 3.0}), 0.95},
+		{mat.NewDense(1, 2, []float64{1.0, 2.0}), mat.NewDense(1, 2, []float64{1.0, 2.0}), 0.95},
+		{a, a, 0},
+		{a, a, 1.0},
+		{mat.NewDense(2, 2, []float64{1.0, 1.0, 2.0, 2.0}), mat.NewDense(2, 2, []float64{1.0, 1.0, 3.0, 3.0}), 0.95},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewDiffConfidence(tc.a, tc.b, tc.c)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
@@ -0,0 +1,60 @@
+package ellipse
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ConicMatrix returns the symmetric 3x3 matrix Q of the ellipse conic equation
+// A*x^2 + B*x*y + C*y^2 + D*x + E*y + F = 0 such that p^T * Q * p = 0 for all
+// the ellipse boundary points p = [x, y, 1] in homogeneous coordinates.
+// The conic equation is the implicit ellipse equation expanded in world coordinates,
+// so p^T * Q * p is equal to Implicit(x, y) for any point [x, y].
+// Under the projective transformation H of points the conic transforms as H^-T * Q * H^-1.
+//
+// For more information see: https://en.wikipedia.org/wiki/Matrix_representation_of_conic_sections
+func (e *Ellipse) ConicMatrix() *mat.SymDense {
+	sin, cos := math.Sincos(e.angle)
+	a2, b2 := e.a*e.a, e.b*e.b
+
+	a := cos*cos/a2 + sin*sin/b2
+	b := 2 * cos * sin * (1/a2 - 1/b2)
+	c := sin*sin/a2 + cos*cos/b2
+	d := -2*a*e.x - b*e.y
+	f := -b*e.x - 2*c*e.y
+	g := a*e.x*e.x + b*e.x*e.y + c*e.y*e.y - 1
+
+	return mat.NewSymDense(3, []float64{
+		a, b / 2, d / 2,
+		b / 2, c, f / 2,
+		d / 2, f / 2, g,
+	})
+}
+
+// DualConicMatrix returns the symmetric 3x3 dual conic matrix Q* of the ellipse i.e. the adjugate
+// of the ellipse conic matrix Q returned by ConicMatrix, which is the inverse of Q up to the scale factor det(Q).
+// The dual conic represents the ellipse as the envelope of its tangent lines:
+// l^T * Q* * l = 0 for all the lines l = [a, b, c] tangent to the ellipse, where a*x + b*y + c = 0.
+// Under the projective transformation H of points the dual conic transforms as H * Q* * H^T.
+//
+// For more information see: https://en.wikipedia.org/wiki/Dual_curve
+func (e *Ellipse) DualConicMatrix() *mat.SymDense {
+	q := e.ConicMatrix()
+
+	// cofactor returns the (i,j) cofactor of q: symmetric matrix has symmetric cofactors
+	cofactor := func(i, j int) float64 {
+		r0, r1 := (i+1)%3, (i+2)%3
+		c0, c1 := (j+1)%3, (j+2)%3
+		return q.At(r0, c0)*q.At(r1, c1) - q.At(r0, c1)*q.At(r1, c0)
+	}
+
+	dual := mat.NewSymDense(3, nil)
+	for i := 0; i < 3; i++ {
+		for j := i; j < 3; j++ {
+			dual.SetSym(i, j, cofactor(i, j))
+		}
+	}
+
+	return dual
+}
@@ -0,0 +1,63 @@
+package ellipse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestConicMatrix(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+	q := ell.ConicMatrix()
+
+	for _, theta := range []float64{0, 0.5, 1.0, 2.0, 4.0} {
+		x, y := ell.PointAt(theta)
+		p := mat.NewVecDense(3, []float64{x, y, 1})
+		assert.InDelta(0, mat.Inner(p, q, p), 1e-9)
+	}
+
+	// the conic matrix is the homogeneous form of the implicit equation
+	p := mat.NewVecDense(3, []float64{2.0, -1.0, 1})
+	assert.InDelta(ell.Implicit(2.0, -1.0), mat.Inner(p, q, p), 1e-9)
+
+	e, err := fromConic(q.At(0, 0), 2*q.At(0, 1), q.At(1, 1), 2*q.At(0, 2), 2*q.At(1, 2), q.At(2, 2))
+	assert.NoError(err)
+	assert.True(ell.ApproxEqual(e, 1e-9))
+}
+
+func TestDualConicMatrix(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+	q := ell.ConicMatrix()
+	dual := ell.DualConicMatrix()
+
+	// the dual conic matrix is the adjugate of the conic matrix i.e. Q * Q* = det(Q) * I
+	var prod mat.Dense
+	prod.Mul(q, dual)
+	det := mat.Det(q)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			exp := 0.0
+			if i == j {
+				exp = det
+			}
+			assert.InDelta(exp, prod.At(i, j), 1e-9)
+		}
+	}
+
+	// tangent lines lie on the dual conic
+	for _, theta := range []float64{0, 0.5, 1.0, 2.0, 4.0} {
+		px, py, nx, ny := ell.NormalAt(theta)
+		l := mat.NewVecDense(3, []float64{nx, ny, -(nx*px + ny*py)})
+		assert.InDelta(0, mat.Inner(l, dual, l), 1e-9)
+	}
+
+	// lines passing through the ellipse do not
+	l := mat.NewVecDense(3, []float64{1, 1, -(ell.x + ell.y)})
+	assert.True(math.Abs(mat.Inner(l, dual, l)) > 1e-3)
+}
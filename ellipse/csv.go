@@ -0,0 +1,75 @@
+package ellipse
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// WriteCSV writes size number of the ellipse boundary points to w as CSV records of X and Y coordinates
+// preceded by "x,y" header record. The points are the same as the ones returned by LinePoints.
+// It returns error if the points could not be written to w.
+// It panics if size is smaller than 2.
+func (e *Ellipse) WriteCSV(w io.Writer, size int) error {
+	xys := e.boundary(size)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"x", "y"}); err != nil {
+		return err
+	}
+
+	for _, xy := range xys {
+		rec := []string{
+			strconv.FormatFloat(xy.X, 'g', -1, 64),
+			strconv.FormatFloat(xy.Y, 'g', -1, 64),
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// NewFromCSV creates new Ellipse by fitting it to the points read from r using the direct least squares method.
+// Each of the CSV records must contain X and Y coordinates of a single point. The first record is skipped
+// if it is a header i.e. if it does not contain the point coordinates.
+// It returns error if any of the records is malformed, if r contains less than 5 points
+// or if the ellipse could not be fitted.
+func NewFromCSV(r io.Reader) (*Ellipse, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+
+	var vals []float64
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid CSV record: %v", err)
+		}
+
+		x, errX := strconv.ParseFloat(rec[0], 64)
+		y, errY := strconv.ParseFloat(rec[1], 64)
+		if errX != nil || errY != nil {
+			// skip the header
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("Invalid CSV record on line %d: %v", line, rec)
+		}
+		vals = append(vals, x, y)
+	}
+
+	if n := len(vals) / 2; n < minFitPoints {
+		return nil, fmt.Errorf("Insufficient number of points: %d", n)
+	}
+
+	return fit(mat.NewDense(len(vals)/2, 2, vals), nil)
+}
@@ -0,0 +1,57 @@
+package ellipse
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+
+	var buf bytes.Buffer
+	assert.NoError(ell.WriteCSV(&buf, 10))
+
+	recs, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(err)
+	assert.Len(recs, 11)
+	assert.Equal([]string{"x", "y"}, recs[0])
+}
+
+func TestNewFromCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	exp := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+
+	var buf bytes.Buffer
+	assert.NoError(exp.WriteCSV(&buf, 20))
+
+	ell, err := NewFromCSV(&buf)
+	assert.NoError(err)
+	assert.True(exp.ApproxEqual(ell, 1e-6))
+
+	// CSV without header
+	data := "4, 0\n0, 1\n-4, 0\n0, -1\n2.8284271247461903, 0.7071067811865476\n"
+	ell, err = NewFromCSV(strings.NewReader(data))
+	assert.NoError(err)
+	assert.True(ell.ApproxEqual(&Ellipse{a: 4.0, b: 1.0}, 1e-6))
+
+	testCases := []string{
+		"x,y\n1,2\n3,4\n",
+		"x,y\n4,0\n0,1\n-4,0\nfoo,bar\n0,-1\n",
+		"4,0\n0,1,2\n-4,0\n0,-1\n1,1\n",
+		"",
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewFromCSV(strings.NewReader(tc))
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
@@ -1,17 +1,47 @@
 package ellipse
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
 
-	"golang.org/x/exp/rand"
 	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/integrate/quad"
 	"gonum.org/v1/gonum/mat"
-	"gonum.org/v1/gonum/stat"
-	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/gonum/mathext"
 	"gonum.org/v1/plot/plotter"
 )
 
+const (
+	// ctxCheckInterval is the number of generated points between context cancellation checks
+	ctxCheckInterval = 1024
+	// maxBisectIter is the maximum number of bisection iterations
+	maxBisectIter = 1100
+	// arcLengthNodes is the number of quadrature nodes used to compute the ellipse arc length
+	arcLengthNodes = 128
+	// maxNewtonIter is the maximum number of Newton iterations
+	maxNewtonIter = 50
+	// maxRefineIter is the maximum number of closest boundary pair refinement iterations
+	maxRefineIter = 100
+	// maxSpacingPoints is the maximum number of points returned by BoundaryBySpacing
+	maxSpacingPoints = 1 << 20
+)
+
+// PerimeterAlgo is the algorithm used to compute the ellipse perimeter.
+type PerimeterAlgo int
+
+const (
+	// Ramanujan1 is the first Ramanujan's approximation whose relative error is below 0.42%.
+	// The error grows with the ellipse eccentricity and approaches its maximum as b/a approaches 0.
+	Ramanujan1 PerimeterAlgo = iota
+	// Ramanujan2 is the second Ramanujan's approximation whose relative error is below 4.1e-4.
+	// The error grows with the ellipse eccentricity and approaches its maximum as b/a approaches 0.
+	Ramanujan2
+	// EllipticIntegral computes the perimeter using the complete elliptic integral of the second kind.
+	EllipticIntegral
+)
+
 // Ellipse is 2D ellipse
 //
 // For more information see: https://en.wikipedia.org/wiki/Ellipse
@@ -21,6 +51,10 @@ type Ellipse struct {
 	a     float64
 	b     float64
 	angle float64
+	// scale is the squared Mahalanobis distance of the ellipse boundary
+	// from the center of the Gaussian distribution the ellipse was created from.
+	// It is 0 if the ellipse was not created from a Gaussian distribution.
+	scale float64
 }
 
 // New creates new Ellipse with origin [x,y], length of major/minor axis (mx,my) and rotation angle radians.
@@ -37,98 +71,898 @@ func New(x, y, a, b, angle float64) (*Ellipse, error) {
 	return &Ellipse{a: a, b: b, angle: angle, x: x, y: y}, nil
 }
 
+// NewNormalized creates new Ellipse just like New does, but it guarantees that a is the semi-major axis.
+// If b is longer than a, the axes are swapped and the rotation angle is increased by pi/2,
+// so the returned ellipse has the same shape as the one created by New.
+// It returns error if either of the axis (a or b) is not positive.
+func NewNormalized(x, y, a, b, angle float64) (*Ellipse, error) {
+	if b > a {
+		a, b = b, a
+		angle += math.Pi / 2
+	}
+
+	return New(x, y, a, b, angle)
+}
+
+// NewWithDirection creates new Ellipse just like New does, but the rotation angle is given by the direction vector [dirX, dirY]
+// of the a semi-axis instead of an angle. The direction vector does not need to be normalized.
+// It returns error if either of the axis (a or b) is not positive or if the direction vector is zero or not finite.
+func NewWithDirection(x, y, a, b, dirX, dirY float64) (*Ellipse, error) {
+	if dirX == 0 && dirY == 0 || math.IsNaN(dirX) || math.IsNaN(dirY) || math.IsInf(dirX, 0) || math.IsInf(dirY, 0) {
+		return nil, fmt.Errorf("Invalid direction vector: (%.2f, %.2f)", dirX, dirY)
+	}
+
+	return New(x, y, a, b, math.Atan2(dirY, dirX))
+}
+
+// NewInRect creates new axis aligned Ellipse inscribed in the rectangle [minX, minY, maxX, maxY]
+// i.e. the ellipse centered in the rectangle whose semi-axes are half of the rectangle width and height.
+// It returns error if the rectangle has zero or negative width or height.
+func NewInRect(minX, minY, maxX, maxY float64) (*Ellipse, error) {
+	if !(minX < maxX) || !(minY < maxY) {
+		return nil, fmt.Errorf("Invalid rectangle: [%.2f, %.2f, %.2f, %.2f]", minX, minY, maxX, maxY)
+	}
+
+	return New((minX+maxX)/2, (minY+maxY)/2, (maxX-minX)/2, (maxY-minY)/2, 0)
+}
+
+// NewFromParams creates new Ellipse from the parameter vector p = [x, y, a, b, angle]
+// which is the inverse of Params i.e. NewFromParams(e.Params()) yields an ellipse equal to e.
+// It returns error if p does not have exactly 5 elements or if either of the axis (a or b) is not positive.
+func NewFromParams(p []float64) (*Ellipse, error) {
+	if len(p) != 5 {
+		return nil, fmt.Errorf("Invalid number of ellipse parameters: %d", len(p))
+	}
+
+	return New(p[0], p[1], p[2], p[3], p[4])
+}
+
 // NewWithDataConfidence creates new Ellipse from data with origin being data mean and confidence probability.
 // The data is assumed to be of the Normal (a.k.a. Gaussian) distribution.
 // It panics if either of the folllowing happens:
 // * supplied data matrix is nil
 // * principal components could not be calculated from the supplied data
-// It returns error if confidence is not in (0,1> interval.
+// It returns error if confidence is not in (0,1) interval or if any of the data covariance eigenvalues is not positive.
 func NewWithDataConfidence(data mat.Matrix, confidence float64) (*Ellipse, error) {
-	if confidence <= 0 || confidence > 1 {
-		return nil, fmt.Errorf("Invalid confidence level: %.2f", confidence)
+	// The sum of square Gaussian is distributed according to Chi-squared distribution:
+	// https://en.wikipedia.org/wiki/Chi-squared_distribution
+	radius, err := ConfidenceToRadius(confidence)
+	if err != nil {
+		return nil, err
 	}
 
-	// calculate x and y mean values
-	rows, _ := data.Dims()
-	vals := make([]float64, rows)
-	xmean := stat.Mean(mat.Col(vals, 0, data), nil)
-	ymean := stat.Mean(mat.Col(vals, 1, data), nil)
-
-	// calculate data eigenvectors and eigenvalues
-	var pc stat.PC
-	ok := pc.PrincipalComponents(data, nil)
-	if !ok {
-		panic("Could not determine Principal Components")
+	m, err := NewConfidenceModel(data)
+	if err != nil {
+		if errors.Is(err, errRankDeficient) {
+			return nil, err
+		}
+		panic(err.Error())
 	}
-	eigVals := pc.VarsTo(nil)
-	var eigVecs mat.Dense
-	pc.VectorsTo(&eigVecs)
 
-	// Calculate Ellipse rotation angle from the largest eigenvector
-	// pc.VectorsTo returns eigenvalues/vectors in descending order
-	angle := math.Atan2(eigVecs.At(0, 1), eigVecs.At(0, 0))
-	if angle < 0 {
-		// Shift the angle to the <0, 2*pi> interval instead of <-pi, pi>
-		angle = angle + 2*math.Pi
+	return m.ellipse(radius), nil
+}
+
+// newFromShape creates new Ellipse with origin [x,y] whose boundary consists of the points p
+// which satisfy d^T * s^-1 * d = 1, where d = p - [x,y]. The eigenvalues of s are the squared semi-axes of the ellipse.
+// It returns error if s is not positive definite.
+func newFromShape(x, y float64, s mat.Symmetric) (*Ellipse, error) {
+	var eig mat.EigenSym
+	if ok := eig.Factorize(s, true); !ok {
+		return nil, fmt.Errorf("Could not determine ellipse axes")
 	}
 
-	// The sum of square Gaussian is distributed according to Chi-squared distribution:
-	// https://en.wikipedia.org/wiki/Chi-squared_distribution
-	src := rand.New(rand.NewSource(1))
-	chi2 := distuv.ChiSquared{K: 2, Src: src}
+	// eigenvalues are returned in ascending order
+	vals := eig.Values(nil)
+	if !(vals[0] > 0) || math.IsInf(vals[1], 0) {
+		return nil, fmt.Errorf("Invalid ellipse shape eigenvalues: %v", vals)
+	}
+	var vecs mat.Dense
+	eig.VectorsTo(&vecs)
 
-	// pc.VarsTo returns eigenvalues in descending order
-	a := math.Sqrt(chi2.Quantile(confidence) * eigVals[0])
-	b := math.Sqrt(chi2.Quantile(confidence) * eigVals[1])
+	// major axis is the eigenvector of the largest eigenvalue
+	e := &Ellipse{
+		x:     x,
+		y:     y,
+		a:     math.Sqrt(vals[1]),
+		b:     math.Sqrt(vals[0]),
+		angle: math.Atan2(vecs.At(1, 1), vecs.At(0, 1)),
+	}
+	e.angle = e.NormalizedAngle()
 
-	return &Ellipse{x: xmean, y: ymean, a: a, b: b, angle: angle}, nil
+	return e, nil
 }
 
 // LinePoints returns both plotter.Line and plotter.Scatter which can be used to plot Ellipse.
 // It returns error if at least one of the ellipse data points contains a NaN or Infinity.
 func (e *Ellipse) LinePoints(size int) (*plotter.Line, *plotter.Scatter, error) {
-	// generate size number of ellipse points
-	points := floats.Span(make([]float64, size), 0, 2*math.Pi)
-	x := make([]float64, len(points))
-	y := make([]float64, len(points))
+	return e.LinePointsContext(context.Background(), size)
+}
+
+// LinePointsContext returns both plotter.Line and plotter.Scatter which can be used to plot Ellipse.
+// The generation of the ellipse points is aborted when ctx is cancelled.
+// It returns ctx.Err() if ctx is cancelled before all the ellipse points are generated
+// or error if at least one of the ellipse data points contains a NaN or Infinity.
+func (e *Ellipse) LinePointsContext(ctx context.Context, size int) (*plotter.Line, *plotter.Scatter, error) {
+	ellipseXYs, err := e.points(ctx, size)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	return plotter.NewLinePoints(ellipseXYs)
+}
+
+// LinePointsSpan returns both plotter.Line and plotter.Scatter which can be used to plot Ellipse
+// using the ellipse boundary points at the parametric angles params.
+// It returns error if params is empty, if any of the params is NaN or Infinity
+// or if at least one of the ellipse data points contains a NaN or Infinity.
+func (e *Ellipse) LinePointsSpan(params []float64) (*plotter.Line, *plotter.Scatter, error) {
+	if len(params) == 0 {
+		return nil, nil, fmt.Errorf("Missing parametric angles")
+	}
+
+	ellipseXYs := make(plotter.XYs, len(params))
+	for i, theta := range params {
+		if math.IsNaN(theta) || math.IsInf(theta, 0) {
+			return nil, nil, fmt.Errorf("Invalid parametric angle: %v", theta)
+		}
+		ellipseXYs[i].X, ellipseXYs[i].Y = e.PointAt(theta)
+	}
+
+	return plotter.NewLinePoints(ellipseXYs)
+}
+
+// points generates size number of ellipse points just like boundary does.
+// It returns ctx.Err() if ctx is cancelled before all the points are generated.
+func (e *Ellipse) points(ctx context.Context, size int) (plotter.XYs, error) {
+	thetas := floats.Span(make([]float64, size), 0, 2*math.Pi)
+	ellipseXYs := make(plotter.XYs, size)
+
+	for i := 0; i < size; i += ctxCheckInterval {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := i + ctxCheckInterval
+		if end > size {
+			end = size
+		}
+		e.boundaryAt(ellipseXYs[i:end], thetas[i:end])
+	}
+
+	return ellipseXYs, nil
+}
+
+// boundary generates size number of ellipse points spread uniformly in the ellipse parameter.
+// The first and the last point are the same point at the parametric angle 0 (2*pi), so callers
+// which need size distinct points should generate size+1 points and drop the last one.
+// It panics if size is smaller than 2.
+func (e *Ellipse) boundary(size int) plotter.XYs {
+	ellipseXYs := make(plotter.XYs, size)
+	e.boundaryAt(ellipseXYs, floats.Span(make([]float64, size), 0, 2*math.Pi))
+
+	return ellipseXYs
+}
+
+// boundaryAt stores the ellipse boundary points at the parametric angles thetas in xys.
+func (e *Ellipse) boundaryAt(xys plotter.XYs, thetas []float64) {
+	for i, theta := range thetas {
+		xys[i].X, xys[i].Y = e.PointAt(theta)
+	}
+}
+
+// BoundaryMatrix returns size x 2 matrix which stores X and Y coordinates of size number of the ellipse boundary points
+// in its 1st and 2nd column. The points are the same as the ones returned by LinePoints.
+// It panics if size is smaller than 2.
+func (e *Ellipse) BoundaryMatrix(size int) *mat.Dense {
+	xys := e.boundary(size)
+
+	m := mat.NewDense(size, 2, nil)
+	for i, xy := range xys {
+		m.Set(i, 0, xy.X)
+		m.Set(i, 1, xy.Y)
+	}
+
+	return m
+}
+
+// PointsAt returns len(thetas) x 2 matrix which stores X and Y coordinates of the ellipse boundary points
+// at the parametric angles thetas in its 1st and 2nd column. The i-th row is the point returned by PointAt(thetas[i]),
+// the rotation by the ellipse angle is however computed only once for all the points.
+// It panics if thetas is empty.
+func (e *Ellipse) PointsAt(thetas []float64) *mat.Dense {
+	m := mat.NewDense(len(thetas), 2, nil)
+
+	sin, cos := math.Sincos(e.angle)
+	for i, theta := range thetas {
+		x := e.a * math.Cos(theta)
+		y := e.b * math.Sin(theta)
+		m.Set(i, 0, x*cos-y*sin+e.x)
+		m.Set(i, 1, x*sin+y*cos+e.y)
+	}
+
+	return m
+}
+
+// CanonicalBoundary returns size number of the ellipse boundary points [a*cos(t), b*sin(t)]
+// in the coordinate system of the ellipse i.e. without rotating and translating them.
+// It panics if size is smaller than 2.
+func (e *Ellipse) CanonicalBoundary(size int) plotter.XYs {
+	c := &Ellipse{a: e.a, b: e.b}
+	xys := c.boundary(size)
+
+	return xys
+}
+
+// PointAt returns the ellipse boundary point at the parametric angle theta.
+func (e *Ellipse) PointAt(theta float64) (float64, float64) {
 	// Parametric representation of ellipse can be obtained as:
 	// (a*cos(angle), b*sin(angl)),  where angle is <0, 2*pi>
-	for i, point := range points {
-		x[i] = e.a * math.Cos(point)
-		y[i] = e.b * math.Sin(point)
+	x := e.a * math.Cos(theta)
+	y := e.b * math.Sin(theta)
+
+	// We need to rotate the point around X axis by angle radians
+	// and shift it by the ellipse origin
+	sin, cos := math.Sincos(e.angle)
+
+	return x*cos - y*sin + e.x, x*sin + y*cos + e.y
+}
+
+// NormalAt returns the ellipse boundary point [px,py] at the parametric angle theta
+// and the outward unit normal [nx,ny] of the ellipse boundary at that point.
+func (e *Ellipse) NormalAt(theta float64) (px, py, nx, ny float64) {
+	px, py = e.PointAt(theta)
+
+	// the outward normal of (a*cos(theta), b*sin(theta)) is parallel to (b*cos(theta), a*sin(theta))
+	u, v := e.b*math.Cos(theta), e.a*math.Sin(theta)
+	l := math.Hypot(u, v)
+	u, v = u/l, v/l
+
+	sin, cos := math.Sincos(e.angle)
+
+	return px, py, u*cos - v*sin, u*sin + v*cos
+}
+
+// PointWithNormal returns the ellipse boundary point [x,y] whose outward normal is parallel to the direction [nx,ny].
+// The direction does not need to be normalized. It returns NaN coordinates if the direction is zero.
+func (e *Ellipse) PointWithNormal(nx, ny float64) (x, y float64) {
+	// rotate the direction into the ellipse coordinate system
+	sin, cos := math.Sincos(e.angle)
+	du, dv := nx*cos+ny*sin, -nx*sin+ny*cos
+
+	// the outward normal of the boundary point (u,v) is parallel to (u/a^2, v/b^2)
+	u, v := e.a*e.a*du, e.b*e.b*dv
+	l := math.Sqrt(u*du + v*dv)
+	u, v = u/l, v/l
+
+	return u*cos - v*sin + e.x, u*sin + v*cos + e.y
+}
+
+// BoundaryJacobian returns 2x5 Jacobian matrix of the ellipse boundary point at the parametric angle theta
+// with respect to the ellipse parameters (x, y, a, b, angle). The rows of the matrix are the derivatives
+// of X and Y coordinates of the boundary point, respectively.
+func (e *Ellipse) BoundaryJacobian(theta float64) *mat.Dense {
+	sinT, cosT := math.Sincos(theta)
+	sin, cos := math.Sincos(e.angle)
+	u, v := e.a*cosT, e.b*sinT
+
+	return mat.NewDense(2, 5, []float64{
+		1, 0, cos * cosT, -sin * sinT, -u*sin - v*cos,
+		0, 1, sin * cosT, cos * sinT, u*cos - v*sin,
+	})
+}
+
+// BoundaryBySpacing returns the ellipse boundary points which are spaced along the ellipse boundary
+// by spacing arc length, starting at the parametric angle 0. The number of the returned points
+// is determined by the ratio of the ellipse perimeter and spacing.
+// It returns error if spacing is not positive or if the number of the points is not finite or exceeds 2^20.
+func (e *Ellipse) BoundaryBySpacing(spacing float64) (plotter.XYs, error) {
+	if !(spacing > 0) {
+		return nil, fmt.Errorf("Invalid spacing: %v", spacing)
 	}
 
-	// ellipse data matrix: it will be rotated in the next step
-	ellipseMx := mat.NewDense(len(points), 2, nil)
-	ellipseMx.SetCol(0, x)
-	ellipseMx.SetCol(1, y)
+	perimeter := e.ArcLength(0, 2*math.Pi)
+	// tolerance of the arc length of the points
+	tol := 1e-12 * perimeter
+
+	n := math.Ceil((perimeter - tol) / spacing)
+	if !(n <= maxSpacingPoints) {
+		return nil, fmt.Errorf("Invalid spacing: %v: too many points: %v", spacing, n)
+	}
+
+	xys := make(plotter.XYs, int(n))
+	for i := range xys {
+		s := float64(i) * spacing
+		// the parametric angle of the point s arc length away from the start is
+		// the root of ArcLength(0, t) - s which is found using Newton's method
+		t := 2 * math.Pi * s / perimeter
+		for j := 0; j < maxNewtonIter; j++ {
+			f := e.ArcLength(0, t) - s
+			if math.Abs(f) < tol {
+				break
+			}
+			sin, cos := math.Sincos(t)
+			t -= f / math.Hypot(e.a*sin, e.b*cos)
+		}
+		xys[i].X, xys[i].Y = e.PointAt(t)
+	}
+
+	return xys, nil
+}
+
+// Implicit returns the value of the implicit ellipse equation (u/a)^2 + (v/b)^2 - 1 at the point [x,y],
+// where [u,v] are the coordinates of the point in the coordinate system of the ellipse.
+// The value is negative inside the ellipse, zero on its boundary and positive outside of it.
+func (e *Ellipse) Implicit(x, y float64) float64 {
+	u, v := e.local(x, y)
+	return (u*u)/(e.a*e.a) + (v*v)/(e.b*e.b) - 1
+}
+
+// ImplicitFunc returns a function which evaluates the implicit ellipse equation just like Implicit does.
+// The ellipse boundary is the zero level set of the returned function, so it can be passed to contour plotters
+// to plot the ellipse without generating its boundary points.
+func (e *Ellipse) ImplicitFunc() func(x, y float64) float64 {
+	return e.Implicit
+}
+
+// ImplicitGradient returns the gradient of the implicit ellipse equation at the point [x,y] in world coordinates.
+// On the ellipse boundary the gradient points in the direction of the outward normal.
+func (e *Ellipse) ImplicitGradient(x, y float64) (gx, gy float64) {
+	u, v := e.local(x, y)
+	du, dv := 2*u/(e.a*e.a), 2*v/(e.b*e.b)
+
+	// rotate the gradient from the ellipse coordinate system back to world coordinates
+	sin, cos := math.Sincos(e.angle)
+
+	return du*cos - dv*sin, du*sin + dv*cos
+}
+
+// SampsonDistance returns the Sampson distance of the point [x,y] from the ellipse
+// i.e. the squared value of the implicit ellipse equation divided by the squared norm of its gradient.
+// Sampson distance is the first order approximation of the squared geometric distance of the point from the ellipse boundary.
+// It returns +Inf for the ellipse center where the gradient vanishes.
+func (e *Ellipse) SampsonDistance(x, y float64) float64 {
+	v := e.Implicit(x, y)
+	gx, gy := e.ImplicitGradient(x, y)
+
+	return v * v / (gx*gx + gy*gy)
+}
+
+// OnBoundary returns true if the point [x,y] lies on the ellipse boundary within tol tolerance
+// of the implicit ellipse equation value.
+func (e *Ellipse) OnBoundary(x, y, tol float64) bool {
+	return math.Abs(e.Implicit(x, y)) <= tol
+}
 
-	// We need to rotate the data around X axis by angle radians
-	rotateData := []float64{
-		math.Cos(e.angle), math.Sin(e.angle),
-		-math.Sin(e.angle), math.Cos(e.angle),
+// Classify classifies the position of the point [x,y] with regards to the ellipse.
+// It returns -1 if the point lies inside the ellipse, 0 if it lies on the ellipse boundary
+// within tol tolerance of the implicit ellipse equation value and +1 if it lies outside the ellipse.
+func (e *Ellipse) Classify(x, y, tol float64) int {
+	v := e.Implicit(x, y)
+	switch {
+	case math.Abs(v) <= tol:
+		return 0
+	case v < 0:
+		return -1
+	default:
+		return 1
 	}
-	rotateMx := mat.NewDense(2, 2, rotateData)
+}
 
-	// Perform data rotation
-	ellipseMx.Mul(ellipseMx, rotateMx)
+// ClosestPoint returns the point on the ellipse boundary which is the closest to the point [x,y].
+// The closest point is found by bisecting the root of the distance function in the ellipse coordinate system.
+//
+// For more information see: D. Eberly: Distance from a Point to an Ellipse, an Ellipsoid, or a Hyperellipsoid
+func (e *Ellipse) ClosestPoint(x, y float64) (float64, float64) {
+	major, minor, angle := e.majorAxis()
+	sin, cos := math.Sincos(angle)
 
-	// get Ellipse data points
-	ellipseXYs := XYFromDense(ellipseMx)
+	// transform the point into the first quadrant of the major axis coordinate system
+	dx, dy := x-e.x, y-e.y
+	u, v := dx*cos+dy*sin, -dx*sin+dy*cos
+	pu, pv := closestPoint(major, minor, math.Abs(u), math.Abs(v))
+	pu, pv = math.Copysign(pu, u), math.Copysign(pv, v)
+
+	return pu*cos - pv*sin + e.x, pu*sin + pv*cos + e.y
+}
+
+// distance returns the Euclidean distance of the point [x,y] from the ellipse boundary.
+func (e *Ellipse) distance(x, y float64) float64 {
+	px, py := e.ClosestPoint(x, y)
+	return math.Hypot(x-px, y-py)
+}
 
-	// we need to shift ellipse points by data mean values
-	for i := range ellipseXYs {
-		ellipseXYs[i].X = ellipseXYs[i].X + e.x
-		ellipseXYs[i].Y = ellipseXYs[i].Y + e.y
+// SignedDistance returns the signed Euclidean distance of the point [x,y] from the ellipse boundary.
+// The distance is negative for the points inside the ellipse and positive for the points outside of it.
+// The distance is computed from the closest boundary point returned by ClosestPoint, so its accuracy
+// is bounded by the accuracy of the root bisection used to find the closest point.
+func (e *Ellipse) SignedDistance(x, y float64) float64 {
+	d := e.distance(x, y)
+	if e.Contains(x, y) {
+		return -d
 	}
 
-	return plotter.NewLinePoints(ellipseXYs)
+	return d
 }
 
-// Eccentricity returns eccentricity of the ellipse
+// Eccentricity returns eccentricity of the ellipse.
+// It does not depend on which of the ellipse semi-axes is the semi-major one.
 func (e *Ellipse) Eccentricity() float64 {
-	return math.Sqrt(1 - (e.a*e.a)/(e.b*e.b))
+	major, minor, _ := e.majorAxis()
+
+	return math.Sqrt(1 - (minor*minor)/(major*major))
+}
+
+// ShapeDescriptor returns the feature vector of the ellipse shape which consists of its area and eccentricity.
+// The descriptor is invariant to the ellipse translation and rotation, so congruent ellipses have the same
+// descriptors, which makes it suitable for clustering ellipses by their shape e.g. with k-means.
+func (e *Ellipse) ShapeDescriptor() [2]float64 {
+	return [2]float64{e.Area(), e.Eccentricity()}
+}
+
+// IsDegenerate returns true if the ratio of the ellipse minor and major semi-axis is smaller than tol
+// i.e. if the ellipse collapses into a sliver which is approximately a line segment.
+// Ellipse whose semi-axes ratio can not be determined e.g. due to NaN semi-axes is considered degenerate, too.
+func (e *Ellipse) IsDegenerate(tol float64) bool {
+	major, minor, _ := e.majorAxis()
+
+	return !(minor/major >= tol)
+}
+
+// NormalizedAngle returns the rotation angle of the ellipse reduced into the <0, pi) interval.
+// Ellipse is symmetric under a half-turn, so angles which differ by a multiple of pi describe the same ellipse.
+func (e *Ellipse) NormalizedAngle() float64 {
+	return normalizeAngle(e.angle)
+}
+
+// normalizeAngle reduces angle into the <0, pi) interval.
+func normalizeAngle(angle float64) float64 {
+	angle = math.Mod(angle, math.Pi)
+	if angle < 0 {
+		angle += math.Pi
+	}
+	// adding pi to a tiny negative angle can round up to pi
+	if angle >= math.Pi {
+		angle = 0
+	}
+
+	return angle
+}
+
+// Foci returns the foci of the ellipse.
+//
+// For more information see: https://en.wikipedia.org/wiki/Ellipse#Definition_as_locus_of_points
+func (e *Ellipse) Foci() (plotter.XY, plotter.XY) {
+	major, minor, angle := e.majorAxis()
+	c := math.Sqrt(major*major - minor*minor)
+	sin, cos := math.Sincos(angle)
+
+	return plotter.XY{X: e.x + c*cos, Y: e.y + c*sin}, plotter.XY{X: e.x - c*cos, Y: e.y - c*sin}
+}
+
+// SemiLatusRectum returns the length of the ellipse semi-latus rectum
+// i.e. half of the length of the chord through a focus perpendicular to the major axis.
+//
+// For more information see: https://en.wikipedia.org/wiki/Ellipse#Semi-latus_rectum
+func (e *Ellipse) SemiLatusRectum() float64 {
+	major, minor, _ := e.majorAxis()
+	return minor * minor / major
+}
+
+// MinCurvatureRadius returns the minimum radius of curvature of the ellipse boundary
+// which is attained at the ellipse major axis vertices. It is equal to the ellipse semi-latus rectum.
+//
+// For more information see: https://en.wikipedia.org/wiki/Ellipse#Curvature
+func (e *Ellipse) MinCurvatureRadius() float64 {
+	return e.SemiLatusRectum()
+}
+
+// MaxCurvatureRadius returns the maximum radius of curvature of the ellipse boundary
+// which is attained at the ellipse minor axis vertices.
+//
+// For more information see: https://en.wikipedia.org/wiki/Ellipse#Curvature
+func (e *Ellipse) MaxCurvatureRadius() float64 {
+	major, minor, _ := e.majorAxis()
+
+	return major * major / minor
+}
+
+// Area returns the area of the ellipse
+func (e *Ellipse) Area() float64 {
+	return math.Pi * e.a * e.b
+}
+
+// SecondMoments returns the second moments of area of the ellipse about its own axes:
+// ixx = pi*a*b^3/4 about the axis of the semi-axis a and iyy = pi*a^3*b/4 about the axis of the semi-axis b.
+//
+// For more information see: https://en.wikipedia.org/wiki/List_of_second_moments_of_area
+func (e *Ellipse) SecondMoments() (ixx, iyy float64) {
+	return math.Pi * e.a * e.b * e.b * e.b / 4, math.Pi * e.a * e.a * e.a * e.b / 4
+}
+
+// InertiaTensor returns 2x2 second moment of area tensor of the ellipse about its center in world coordinates
+// i.e. the diagonal tensor of SecondMoments rotated by the ellipse rotation angle.
+// The eigenvectors of the tensor are the directions of the ellipse axes.
+func (e *Ellipse) InertiaTensor() *mat.Dense {
+	ixx, iyy := e.SecondMoments()
+	sin, cos := math.Sincos(e.angle)
+
+	// R * diag(ixx, iyy) * R^T
+	xx := ixx*cos*cos + iyy*sin*sin
+	yy := ixx*sin*sin + iyy*cos*cos
+	xy := (ixx - iyy) * sin * cos
+
+	return mat.NewDense(2, 2, []float64{xx, xy, xy, yy})
+}
+
+// Perimeter returns the perimeter of the ellipse.
+// The perimeter is computed using Ramanujan's approximation:
+// https://en.wikipedia.org/wiki/Ellipse#Circumference
+func (e *Ellipse) Perimeter() float64 {
+	return math.Pi * (3*(e.a+e.b) - math.Sqrt((3*e.a+e.b)*(e.a+3*e.b)))
+}
+
+// PerimeterExact returns the perimeter of the ellipse computed using
+// the complete elliptic integral of the second kind E(m), where m = 1 - (minor/major)^2.
+// It is more accurate, but slower than the approximation returned by Perimeter.
+//
+// For more information see: https://en.wikipedia.org/wiki/Elliptic_integral#Complete_elliptic_integral_of_the_second_kind
+func (e *Ellipse) PerimeterExact() float64 {
+	major, minor, _ := e.majorAxis()
+	m := 1 - (minor/major)*(minor/major)
+
+	return 4 * major * mathext.CompleteE(m)
+}
+
+// PerimeterMethod returns the perimeter of the ellipse computed using the algorithm m.
+// It panics if m is not a supported perimeter algorithm.
+//
+// For more information see: https://en.wikipedia.org/wiki/Ellipse#Circumference
+func (e *Ellipse) PerimeterMethod(m PerimeterAlgo) float64 {
+	switch m {
+	case Ramanujan1:
+		return e.Perimeter()
+	case Ramanujan2:
+		h := (e.a - e.b) * (e.a - e.b) / ((e.a + e.b) * (e.a + e.b))
+		return math.Pi * (e.a + e.b) * (1 + 3*h/(10+math.Sqrt(4-3*h)))
+	case EllipticIntegral:
+		return e.PerimeterExact()
+	}
+
+	panic(fmt.Sprintf("Unsupported perimeter algorithm: %d", m))
+}
+
+// ArcLength returns the length of the ellipse arc between the parametric angles t0 and t1.
+// The arc length is computed by numerical integration using Gauss-Legendre quadrature.
+// The returned length is negative if t1 is smaller than t0.
+// The full turns of the arc are integrated only once, so arbitrarily long arcs are computed in constant time.
+// It returns NaN if either t0 or t1 is NaN or Infinity.
+func (e *Ellipse) ArcLength(t0, t1 float64) float64 {
+	if math.IsNaN(t0) || math.IsNaN(t1) || math.IsInf(t0, 0) || math.IsInf(t1, 0) {
+		return math.NaN()
+	}
+
+	if t1 < t0 {
+		return -e.ArcLength(t1, t0)
+	}
+
+	turns := math.Floor((t1 - t0) / (2 * math.Pi))
+	if turns == 0 {
+		return e.arcLength(t0, t1)
+	}
+
+	rest := math.Max(0, (t1-t0)-turns*2*math.Pi)
+
+	return turns*e.arcLength(0, 2*math.Pi) + e.arcLength(t0, t0+rest)
+}
+
+// arcLength returns the length of the ellipse arc between the parametric angles t0 and t1, where t0 <= t1.
+func (e *Ellipse) arcLength(t0, t1 float64) float64 {
+	if t1 == t0 {
+		return 0
+	}
+
+	speed := func(t float64) float64 {
+		sin, cos := math.Sincos(t)
+		return math.Hypot(e.a*sin, e.b*cos)
+	}
+
+	// the arc is integrated per quarter-turn segments to keep the integration accurate for elongated ellipses
+	n := math.Ceil((t1 - t0) / (math.Pi / 2))
+	step := (t1 - t0) / n
+
+	length := 0.0
+	for i := 0.0; i < n; i++ {
+		length += quad.Fixed(speed, t0+i*step, t0+(i+1)*step, arcLengthNodes, nil, 0)
+	}
+
+	return length
+}
+
+// Diameter returns the length of the longest chord of the ellipse i.e. the length of its major axis.
+func (e *Ellipse) Diameter() float64 {
+	return 2 * math.Max(e.a, e.b)
+}
+
+// Width returns the length of the shortest chord through the ellipse center i.e. the length of its minor axis.
+func (e *Ellipse) Width() float64 {
+	return 2 * math.Min(e.a, e.b)
+}
+
+// BoundingCircle returns the smallest circle which contains the ellipse as an Ellipse with equal semi-axes
+// i.e. the circle centered at the ellipse center whose radius is the ellipse semi-major axis.
+// The returned circle does not carry the Gaussian distribution metadata.
+// Testing the bounding circles for overlap is a cheap first test of whether two ellipses can overlap.
+func (e *Ellipse) BoundingCircle() *Ellipse {
+	r := math.Max(e.a, e.b)
+
+	return &Ellipse{x: e.x, y: e.y, a: r, b: r}
+}
+
+// Contains returns true if the point [x,y] lies inside the ellipse or on its boundary.
+func (e *Ellipse) Contains(x, y float64) bool {
+	return e.Implicit(x, y) <= 0
+}
+
+// LabelPoints labels the points stored in the first two columns of data.
+// It returns a slice with a label for every data row which is true if the point lies inside the ellipse.
+// It returns error if data has less than 2 columns.
+func (e *Ellipse) LabelPoints(data mat.Matrix) ([]bool, error) {
+	rows, cols := data.Dims()
+	if cols < 2 {
+		return nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+
+	labels := make([]bool, rows)
+	for i := range labels {
+		labels[i] = e.Contains(data.At(i, 0), data.At(i, 1))
+	}
+
+	return labels, nil
+}
+
+// CountInside returns the number of the points stored in the first two columns of data which lie inside the ellipse.
+// It returns error if data has less than 2 columns.
+func (e *Ellipse) CountInside(data mat.Matrix) (int, error) {
+	labels, err := e.LabelPoints(data)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, inside := range labels {
+		if inside {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// BoundaryFractionInside returns the fraction of the ellipse boundary which lies inside other ellipse.
+// The fraction is estimated from size number of the ellipse boundary points spaced uniformly in the parametric angle.
+// It panics if size is not positive.
+func (e *Ellipse) BoundaryFractionInside(other *Ellipse, size int) float64 {
+	xys := e.boundary(size + 1)
+
+	inside := 0
+	// the last point closes the boundary
+	for _, xy := range xys[:size] {
+		if other.Contains(xy.X, xy.Y) {
+			inside++
+		}
+	}
+
+	return float64(inside) / float64(size)
+}
+
+// BoundingBox returns the axis aligned bounding box of the ellipse as minX, minY, maxX, maxY.
+func (e *Ellipse) BoundingBox() (float64, float64, float64, float64) {
+	sin, cos := math.Sincos(e.angle)
+	w := math.Hypot(e.a*cos, e.b*sin)
+	h := math.Hypot(e.a*sin, e.b*cos)
+
+	return e.x - w, e.y - h, e.x + w, e.y + h
+}
+
+// InscribedRect returns the largest area axis aligned rectangle inscribed in the ellipse as minX, minY, maxX, maxY.
+// The rectangle is centered at the ellipse center and its corners [±w,±h] relative to the center lie inside the ellipse
+// iff A*w^2 + C*h^2 + |B|*w*h <= 1, where A*x^2 + B*x*y + C*y^2 = 1 is the equation of the centered ellipse.
+// The area w*h is maximized when sqrt(A)*w = sqrt(C)*h, so for an axis aligned ellipse the rectangle
+// half-dimensions are a/sqrt(2) and b/sqrt(2).
+func (e *Ellipse) InscribedRect() (minX, minY, maxX, maxY float64) {
+	sin, cos := math.Sincos(e.angle)
+	a2, b2 := e.a*e.a, e.b*e.b
+
+	a := cos*cos/a2 + sin*sin/b2
+	b := 2 * cos * sin * (1/a2 - 1/b2)
+	c := sin*sin/a2 + cos*cos/b2
+
+	// the largest product of the rectangle half-dimensions
+	wh := 1 / (2*math.Sqrt(a*c) + math.Abs(b))
+	w := math.Sqrt(wh * math.Sqrt(c/a))
+	h := math.Sqrt(wh * math.Sqrt(a/c))
+
+	return e.x - w, e.y - h, e.x + w, e.y + h
+}
+
+// ExtremePoints returns the ellipse boundary points with the largest and the smallest Y coordinate (top, bottom)
+// and the smallest and the largest X coordinate (left, right). The points touch the ellipse bounding box.
+func (e *Ellipse) ExtremePoints() (top, bottom, left, right plotter.XY) {
+	sin, cos := math.Sincos(e.angle)
+
+	// world X and Y coordinates of the boundary points are sinusoids of the parametric angle:
+	// x - e.x = a*cos(angle)*cos(t) - b*sin(angle)*sin(t) attains its maximum at atan2(-b*sin(angle), a*cos(angle))
+	// y - e.y = a*sin(angle)*cos(t) + b*cos(angle)*sin(t) attains its maximum at atan2(b*cos(angle), a*sin(angle))
+	tx := math.Atan2(-e.b*sin, e.a*cos)
+	ty := math.Atan2(e.b*cos, e.a*sin)
+
+	top.X, top.Y = e.PointAt(ty)
+	bottom.X, bottom.Y = e.PointAt(ty + math.Pi)
+	left.X, left.Y = e.PointAt(tx + math.Pi)
+	right.X, right.Y = e.PointAt(tx)
+
+	return top, bottom, left, right
+}
+
+// TightRect returns the smallest rectangle which contains the ellipse i.e. the bounding box of the ellipse
+// aligned with its axes. The rectangle width and height are the lengths of the ellipse axes a and b,
+// respectively, and it is rotated around its center by angle which is the normalized ellipse rotation angle.
+func (e *Ellipse) TightRect() (width, height float64, center plotter.XY, angle float64) {
+	return 2 * e.a, 2 * e.b, plotter.XY{X: e.x, Y: e.y}, e.NormalizedAngle()
+}
+
+// CenterDistance returns the Euclidean distance between the centers of e and other.
+func (e *Ellipse) CenterDistance(other *Ellipse) float64 {
+	return math.Hypot(e.x-other.x, e.y-other.y)
+}
+
+// ClosestBoundaryPair returns the closest pair of the boundary points of e and other and the distance between them.
+// The pair is first searched among size number of the boundary points of each of the ellipses and then it is refined
+// by alternately projecting the points onto the boundaries of the ellipses for as long as the distance decreases.
+// It panics if size is smaller than 2.
+func (e *Ellipse) ClosestBoundaryPair(other *Ellipse, size int) (pA, pB plotter.XY, dist float64) {
+	xysA := e.boundary(size)
+	xysB := other.boundary(size)
+
+	dist = math.Inf(1)
+	for _, a := range xysA {
+		for _, b := range xysB {
+			if d := math.Hypot(a.X-b.X, a.Y-b.Y); d < dist {
+				pA, pB, dist = a, b, d
+			}
+		}
+	}
+
+	for i := 0; i < maxRefineIter; i++ {
+		var a, b plotter.XY
+		b.X, b.Y = other.ClosestPoint(pA.X, pA.Y)
+		a.X, a.Y = e.ClosestPoint(b.X, b.Y)
+
+		d := math.Hypot(a.X-b.X, a.Y-b.Y)
+		if !(d < dist) {
+			break
+		}
+		pA, pB, dist = a, b, d
+	}
+
+	return pA, pB, dist
+}
+
+// MajorAxisAngleTo returns the angle between the major axes of e and other in the <0, pi/2> interval.
+// The major axes are undirected, so the angle between them is at most pi/2.
+func (e *Ellipse) MajorAxisAngleTo(other *Ellipse) float64 {
+	_, _, angle1 := e.majorAxis()
+	_, _, angle2 := other.majorAxis()
+
+	angle := math.Abs(math.Mod(angle1-angle2, math.Pi))
+
+	return math.Min(angle, math.Pi-angle)
+}
+
+// majorAxis returns the lengths of the semi-major and semi-minor axis and the rotation angle of the major axis.
+func (e *Ellipse) majorAxis() (float64, float64, float64) {
+	if e.b > e.a {
+		return e.b, e.a, e.angle + math.Pi/2
+	}
+
+	return e.a, e.b, e.angle
+}
+
+// closestPoint returns the point on the boundary of the axis aligned ellipse centered at the origin
+// with semi-axes e0 >= e1 which is the closest to the point [y0,y1] lying in the first quadrant.
+func closestPoint(e0, e1, y0, y1 float64) (float64, float64) {
+	if y1 > 0 {
+		if y0 > 0 {
+			z0, z1 := y0/e0, y1/e1
+			g := z0*z0 + z1*z1 - 1
+			if g != 0 {
+				r0 := (e0 / e1) * (e0 / e1)
+				s := bisectRoot(r0, z0, z1, g)
+				return r0 * y0 / (s + r0), y1 / (s + 1)
+			}
+			return y0, y1
+		}
+		return 0, e1
+	}
+
+	numer0, denom0 := e0*y0, e0*e0-e1*e1
+	if numer0 < denom0 {
+		xde0 := numer0 / denom0
+		return e0 * xde0, e1 * math.Sqrt(1-xde0*xde0)
+	}
+
+	return e0, 0
+}
+
+// bisectRoot finds the root of the function (r0*z0/(s+r0))^2 + (z1/(s+1))^2 - 1 using bisection.
+func bisectRoot(r0, z0, z1, g float64) float64 {
+	n0 := r0 * z0
+	s0, s1 := z1-1, 0.0
+	if g >= 0 {
+		s1 = math.Hypot(n0, z1) - 1
+	}
+
+	s := 0.0
+	for i := 0; i < maxBisectIter; i++ {
+		s = (s0 + s1) / 2
+		if s == s0 || s == s1 {
+			break
+		}
+		ratio0, ratio1 := n0/(s+r0), z1/(s+1)
+		g = ratio0*ratio0 + ratio1*ratio1 - 1
+		switch {
+		case g > 0:
+			s0 = s
+		case g < 0:
+			s1 = s
+		default:
+			return s
+		}
+	}
+
+	return s
+}
+
+// local transforms the point [x,y] into the coordinate system of the ellipse
+// i.e. the coordinate system with origin in the ellipse center and axes aligned with the ellipse axes.
+func (e *Ellipse) local(x, y float64) (float64, float64) {
+	sin, cos := math.Sincos(e.angle)
+	dx, dy := x-e.x, y-e.y
+
+	return dx*cos + dy*sin, -dx*sin + dy*cos
+}
+
+// ApproxEqual returns true if e and other describe the same ellipse within tol tolerance.
+// Both ellipses are normalized before they are compared so that their first semi-axis is the semi-major one,
+// so an ellipse is equal to the same ellipse with swapped axes and rotation angle shifted by pi/2.
+// The rotation angles are compared modulo pi, since ellipse is symmetric under a half-turn,
+// and they are ignored altogether if both ellipses are circles within tol tolerance.
+func (e *Ellipse) ApproxEqual(other *Ellipse, tol float64) bool {
+	ma1, mi1, angle1 := e.majorAxis()
+	ma2, mi2, angle2 := other.majorAxis()
+
+	if math.Abs(e.x-other.x) > tol ||
+		math.Abs(e.y-other.y) > tol ||
+		math.Abs(ma1-ma2) > tol ||
+		math.Abs(mi1-mi2) > tol {
+		return false
+	}
+
+	// rotation angle of a circle is arbitrary
+	if ma1-mi1 <= tol && ma2-mi2 <= tol {
+		return true
+	}
+
+	angle := math.Abs(math.Mod(angle1-angle2, math.Pi))
+	angle = math.Min(angle, math.Pi-angle)
+
+	return angle <= tol
+}
+
+// Params returns the ellipse parameter vector [x, y, a, b, angle] which can be passed to numeric optimizers.
+// The parameters are ordered the same way as the columns of the Jacobian returned by BoundaryJacobian.
+// The Gaussian distribution metadata is not included in the returned vector.
+func (e *Ellipse) Params() []float64 {
+	return []float64{e.x, e.y, e.a, e.b, e.angle}
 }
 
 // String implements fmt.Stringer interface
@@ -148,3 +982,17 @@ func XYFromDense(m *mat.Dense) plotter.XYs {
 
 	return pts
 }
+
+// XYFromDenseErr returns plotter.XYs from m, which stores X and Y coordinates in its 1st and 2nd column.
+// Unlike XYFromDense, it returns error if either m is nil or if m doesn't have at least 2 columns.
+func XYFromDenseErr(m *mat.Dense) (plotter.XYs, error) {
+	if m == nil {
+		return nil, fmt.Errorf("Missing data matrix")
+	}
+
+	if _, cols := m.Dims(); cols < 2 {
+		return nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+
+	return XYFromDense(m), nil
+}
@@ -1,11 +1,14 @@
 package ellipse
 
 import (
+	"context"
 	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot/plotter"
 )
 
 func TestNewEllipse(t *testing.T) {
@@ -38,6 +41,148 @@ func TestNewEllipse(t *testing.T) {
 	}
 }
 
+func TestNewNormalized(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		x     float64
+		y     float64
+		a     float64
+		b     float64
+		angle float64
+		expA  float64
+		expB  float64
+		expAn float64
+		err   bool
+	}{
+		{0, 0, 1.0, 5.0, 0, 5.0, 1.0, math.Pi / 2, false},
+		{1.0, 2.0, 5.0, 1.0, 0.3, 5.0, 1.0, 0.3, false},
+		{0, 0, 2.0, 2.0, 0.3, 2.0, 2.0, 0.3, false},
+		{0, 0, 0, 1.0, 0, 0, 0, 0, true},
+		{0, 0, 1.0, -1.0, 0, 0, 0, 0, true},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewNormalized(tc.x, tc.y, tc.a, tc.b, tc.angle)
+		if tc.err {
+			assert.Error(err)
+			assert.Nil(ell)
+			continue
+		}
+		assert.NoError(err)
+		assert.Equal(tc.expA, ell.a)
+		assert.Equal(tc.expB, ell.b)
+		assert.InDelta(tc.expAn, ell.angle, 1e-12)
+
+		// the normalized ellipse must have the same shape as the original one
+		orig, err := New(tc.x, tc.y, tc.a, tc.b, tc.angle)
+		assert.NoError(err)
+		minX, minY, maxX, maxY := orig.BoundingBox()
+		nminX, nminY, nmaxX, nmaxY := ell.BoundingBox()
+		assert.InDeltaSlice([]float64{minX, minY, maxX, maxY}, []float64{nminX, nminY, nmaxX, nmaxY}, 1e-9)
+		assert.Equal(orig.Area(), ell.Area())
+	}
+}
+
+func TestNewWithDirection(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		dirX  float64
+		dirY  float64
+		a     float64
+		expAn float64
+		err   bool
+	}{
+		{0, 1.0, 2.0, math.Pi / 2, false},
+		{1.0, 0, 2.0, 0, false},
+		{3.0, 3.0, 2.0, math.Pi / 4, false},
+		{-2.0, 0, 2.0, math.Pi, false},
+		{0, 0, 2.0, 0, true},
+		{math.NaN(), 1.0, 2.0, 0, true},
+		{1.0, math.Inf(-1), 2.0, 0, true},
+		{0, 1.0, 0, 0, true},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewWithDirection(1.0, 2.0, tc.a, 1.0, tc.dirX, tc.dirY)
+		if tc.err {
+			assert.Error(err)
+			assert.Nil(ell)
+			continue
+		}
+		assert.NoError(err)
+		assert.InDelta(tc.expAn, ell.angle, 1e-12)
+
+		exp, err := New(1.0, 2.0, tc.a, 1.0, tc.expAn)
+		assert.NoError(err)
+		assert.True(exp.ApproxEqual(ell, 1e-12))
+	}
+}
+
+func TestNewInRect(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := [][4]float64{
+		{0, 0, 2.0, 2.0},
+		{-1.0, 2.0, 5.0, 3.0},
+		{-4.0, -3.0, -2.0, 7.0},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewInRect(tc[0], tc[1], tc[2], tc[3])
+		assert.NoError(err)
+		minX, minY, maxX, maxY := ell.BoundingBox()
+		assert.InDeltaSlice(tc[:], []float64{minX, minY, maxX, maxY}, 1e-12)
+		assert.Zero(ell.angle)
+	}
+
+	testCases = [][4]float64{
+		{0, 0, 0, 2.0},
+		{0, 2.0, 2.0, 2.0},
+		{2.0, 0, 0, 2.0},
+		{0, 2.0, 2.0, 0},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewInRect(tc[0], tc[1], tc[2], tc[3])
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestParams(t *testing.T) {
+	assert := assert.New(t)
+
+	ell, err := New(1.0, -2.0, 3.0, 1.5, math.Pi/5)
+	assert.NoError(err)
+
+	p := ell.Params()
+	assert.Equal([]float64{1.0, -2.0, 3.0, 1.5, math.Pi / 5}, p)
+
+	// modifying the returned parameters does not modify the ellipse
+	p[2] = 10.0
+	assert.Equal(3.0, ell.a)
+
+	e, err := NewFromParams(ell.Params())
+	assert.NoError(err)
+	assert.Equal(ell, e)
+
+	testCases := [][]float64{
+		nil,
+		{1.0, 2.0, 3.0, 4.0},
+		{1.0, 2.0, 3.0, 4.0, 0, 1.0},
+		{1.0, 2.0, 0, 4.0, 0},
+		{1.0, 2.0, 3.0, -4.0, 0},
+	}
+
+	for _, tc := range testCases {
+		e, err := NewFromParams(tc)
+		assert.Error(err)
+		assert.Nil(e)
+	}
+}
+
 func TestNewWithConfidence(t *testing.T) {
 	assert := assert.New(t)
 
@@ -48,7 +193,10 @@ func TestNewWithConfidence(t *testing.T) {
 	}{
 		{mat.NewDense(2, 2, []float64{1.0, 2.0, 1.0, 2.0}), 0, true},
 		{mat.NewDense(2, 2, []float64{1.0, 2.0, 1.0, 2.0}), 2.0, true},
-		{mat.NewDense(2, 2, []float64{1.0, 2.0, 1.0, 2.0}), 0.05, false},
+		// rank deficient data yield zero covariance eigenvalues
+		{mat.NewDense(2, 2, []float64{1.0, 2.0, 1.0, 2.0}), 0.05, true},
+		{mat.NewDense(3, 2, []float64{1.0, 2.0, 3.0, 2.0, 5.0, 2.0}), 0.05, true},
+		{mat.NewDense(3, 2, []float64{1.0, 2.0, 3.0, 5.0, 5.0, 3.0}), 0.05, false},
 	}
 
 	for _, tc := range testCases {
@@ -63,6 +211,105 @@ func TestNewWithConfidence(t *testing.T) {
 	}
 }
 
+func TestLinePointsSpan(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+
+	// logarithmically spaced parametric angles
+	params := []float64{0.001, 0.01, 0.1, 1.0, 5.0}
+	line, points, err := ell.LinePointsSpan(params)
+	assert.NoError(err)
+	assert.NotNil(line)
+	assert.Equal(len(params), points.Len())
+	for i, theta := range params {
+		x, y := ell.PointAt(theta)
+		assert.Equal(plotter.XY{X: x, Y: y}, points.XYs[i])
+	}
+
+	// uniformly spaced parametric angles match LinePoints
+	_, exp, err := ell.LinePoints(10)
+	assert.NoError(err)
+	_, points, err = ell.LinePointsSpan(floats.Span(make([]float64, 10), 0, 2*math.Pi))
+	assert.NoError(err)
+	assert.Equal(exp.XYs, points.XYs)
+
+	testCases := [][]float64{
+		nil,
+		{},
+		{0, math.NaN()},
+		{math.Inf(1), 1.0},
+	}
+
+	for _, tc := range testCases {
+		line, points, err := ell.LinePointsSpan(tc)
+		assert.Error(err)
+		assert.Nil(line)
+		assert.Nil(points)
+	}
+}
+
+func TestBoundaryMatrix(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+
+	for _, size := range []int{2, 10, 100} {
+		m := ell.BoundaryMatrix(size)
+		r, c := m.Dims()
+		assert.Equal(size, r)
+		assert.Equal(2, c)
+
+		_, points, err := ell.LinePoints(size)
+		assert.NoError(err)
+		assert.Equal(points.XYs, XYFromDense(m))
+	}
+
+	assert.Panics(func() { ell.BoundaryMatrix(1) })
+}
+
+func TestPointsAt(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+
+	thetas := []float64{0, 0.5, math.Pi / 2, 2.0, math.Pi, 4.5, -1.0, 10.0}
+	m := ell.PointsAt(thetas)
+	r, c := m.Dims()
+	assert.Equal(len(thetas), r)
+	assert.Equal(2, c)
+
+	for i, theta := range thetas {
+		x, y := ell.PointAt(theta)
+		assert.Equal(x, m.At(i, 0))
+		assert.Equal(y, m.At(i, 1))
+	}
+
+	assert.Panics(func() { ell.PointsAt(nil) })
+}
+
+func TestCanonicalBoundary(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+
+	xys := ell.CanonicalBoundary(100)
+	assert.Len(xys, 100)
+
+	var sumX, sumY float64
+	for _, xy := range xys {
+		assert.InDelta(1.0, (xy.X/ell.a)*(xy.X/ell.a)+(xy.Y/ell.b)*(xy.Y/ell.b), 1e-12)
+		assert.True(math.Abs(xy.X) <= ell.a && math.Abs(xy.Y) <= ell.b)
+		sumX += xy.X
+		sumY += xy.Y
+	}
+	// the points are centered at the origin; the first and the last point coincide
+	assert.InDelta(0, (sumX-xys[99].X)/99, 1e-12)
+	assert.InDelta(0, (sumY-xys[99].Y)/99, 1e-12)
+	assert.InDelta(ell.a, xys[0].X, 1e-12)
+	assert.InDelta(0, xys[0].Y, 1e-12)
+}
+
 func TestLinePoints(t *testing.T) {
 	assert := assert.New(t)
 
@@ -75,12 +322,979 @@ func TestLinePoints(t *testing.T) {
 	assert.Equal(size, points.Len())
 }
 
+func TestLinePointsContext(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{a: 1.0, b: 3.0, angle: math.Pi}
+	size := 10
+
+	line, points, err := ell.LinePointsContext(context.Background(), size)
+	assert.NoError(err)
+	assert.NotNil(line)
+	assert.Equal(size, points.Len())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	line, points, err = ell.LinePointsContext(ctx, 1000000)
+	assert.Equal(context.Canceled, err)
+	assert.Nil(line)
+	assert.Nil(points)
+}
+
+func TestPointAt(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 2}
+
+	testCases := []struct {
+		theta float64
+		x     float64
+		y     float64
+	}{
+		{0, 1.0, 5.0},
+		{math.Pi / 2, 0, 2.0},
+		{math.Pi, 1.0, -1.0},
+		{3 * math.Pi / 2, 2.0, 2.0},
+	}
+
+	for _, tc := range testCases {
+		x, y := ell.PointAt(tc.theta)
+		assert.InDelta(tc.x, x, 1e-9)
+		assert.InDelta(tc.y, y, 1e-9)
+	}
+}
+
+func TestBoundaryJacobian(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+	h := 1e-6
+
+	// perturb returns a copy of ell with its i-th parameter shifted by d
+	perturb := func(i int, d float64) *Ellipse {
+		c := *ell
+		params := []*float64{&c.x, &c.y, &c.a, &c.b, &c.angle}
+		*params[i] += d
+		return &c
+	}
+
+	for _, theta := range []float64{0, 0.5, math.Pi / 2, 2.0, 4.0} {
+		jac := ell.BoundaryJacobian(theta)
+		r, c := jac.Dims()
+		assert.Equal(2, r)
+		assert.Equal(5, c)
+
+		for i := 0; i < 5; i++ {
+			x1, y1 := perturb(i, h).PointAt(theta)
+			x0, y0 := perturb(i, -h).PointAt(theta)
+			assert.InDelta((x1-x0)/(2*h), jac.At(0, i), 1e-6)
+			assert.InDelta((y1-y0)/(2*h), jac.At(1, i), 1e-6)
+		}
+	}
+}
+
+func TestNormalAt(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0}
+
+	px, py, nx, ny := ell.NormalAt(0)
+	assert.InDelta(4.0, px, 1e-9)
+	assert.InDelta(2.0, py, 1e-9)
+	assert.InDelta(1.0, nx, 1e-9)
+	assert.InDelta(0, ny, 1e-9)
+
+	px, py, nx, ny = ell.NormalAt(math.Pi / 2)
+	assert.InDelta(1.0, px, 1e-9)
+	assert.InDelta(3.0, py, 1e-9)
+	assert.InDelta(0, nx, 1e-9)
+	assert.InDelta(1.0, ny, 1e-9)
+
+	ell.angle = math.Pi / 3
+	for _, theta := range []float64{0.5, 1.0, 2.0, 4.0} {
+		px, py, nx, ny := ell.NormalAt(theta)
+		x, y := ell.PointAt(theta)
+		assert.Equal(x, px)
+		assert.Equal(y, py)
+		assert.InDelta(1.0, math.Hypot(nx, ny), 1e-9)
+
+		// the normal is parallel to the implicit equation gradient
+		gx, gy := ell.ImplicitGradient(px, py)
+		g := math.Hypot(gx, gy)
+		assert.InDelta(gx/g, nx, 1e-9)
+		assert.InDelta(gy/g, ny, 1e-9)
+	}
+}
+
+func TestPointWithNormal(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0}
+
+	x, y := ell.PointWithNormal(1.0, 0)
+	assert.InDelta(4.0, x, 1e-9)
+	assert.InDelta(2.0, y, 1e-9)
+
+	x, y = ell.PointWithNormal(0, -2.0)
+	assert.InDelta(1.0, x, 1e-9)
+	assert.InDelta(1.0, y, 1e-9)
+
+	ell.angle = math.Pi / 3
+	for _, theta := range []float64{0.5, 1.0, 2.0, 4.0} {
+		px, py, nx, ny := ell.NormalAt(theta)
+		x, y := ell.PointWithNormal(2*nx, 2*ny)
+		assert.InDelta(px, x, 1e-9)
+		assert.InDelta(py, y, 1e-9)
+	}
+
+	x, y = ell.PointWithNormal(0, 0)
+	assert.True(math.IsNaN(x))
+	assert.True(math.IsNaN(y))
+}
+
+func TestImplicit(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+
+	assert.InDelta(-1.0, ell.Implicit(1.0, 2.0), 1e-9)
+	for _, theta := range []float64{0, 0.5, 1.0, 2.0, 4.0} {
+		x, y := ell.PointAt(theta)
+		assert.InDelta(0, ell.Implicit(x, y), 1e-9)
+	}
+	assert.True(ell.Implicit(10.0, 10.0) > 0)
+}
+
+func TestImplicitFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+	f := ell.ImplicitFunc()
+
+	for _, theta := range []float64{0, 0.5, 1.0, 2.0, 4.0} {
+		x, y := ell.PointAt(theta)
+		assert.InDelta(0, f(x, y), 1e-9)
+	}
+	assert.Equal(ell.Implicit(1.0, 2.0), f(1.0, 2.0))
+	assert.Equal(ell.Implicit(10.0, -3.0), f(10.0, -3.0))
+}
+
+func TestOnBoundary(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+
+	for _, theta := range []float64{0, 0.5, 1.0, 2.0, 4.0} {
+		x, y := ell.PointAt(theta)
+		assert.True(ell.OnBoundary(x, y, 1e-9))
+	}
+	assert.False(ell.OnBoundary(1.0, 2.0, 1e-9))
+	assert.False(ell.OnBoundary(1.5, 2.0, 0.1))
+}
+
+func TestImplicitGradient(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+	sin, cos := math.Sincos(ell.angle)
+
+	for _, theta := range []float64{0, 0.5, 1.0, 2.0, 4.0, 5.5} {
+		x, y := ell.PointAt(theta)
+		gx, gy := ell.ImplicitGradient(x, y)
+
+		// the gradient is perpendicular to the boundary tangent
+		tu, tv := -ell.a*math.Sin(theta), ell.b*math.Cos(theta)
+		tx, ty := tu*cos-tv*sin, tu*sin+tv*cos
+		assert.InDelta(0, gx*tx+gy*ty, 1e-9)
+
+		// the gradient points outwards
+		assert.True(gx*(x-ell.x)+gy*(y-ell.y) > 0)
+		assert.False(ell.Contains(x+1e-3*gx, y+1e-3*gy))
+		assert.True(ell.Contains(x-1e-3*gx, y-1e-3*gy))
+	}
+
+	gx, gy := ell.ImplicitGradient(ell.x, ell.y)
+	assert.Zero(gx)
+	assert.Zero(gy)
+}
+
+func TestSampsonDistance(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+	sin, cos := math.Sincos(ell.angle)
+
+	for _, d := range []float64{-0.01, -0.001, 0.001, 0.01} {
+		// points on the major and minor axis d distance away from the vertices
+		ax, ay := ell.PointAt(0)
+		bx, by := ell.PointAt(math.Pi / 2)
+		points := [][2]float64{
+			{ax + d*cos, ay + d*sin},
+			{bx - d*sin, by + d*cos},
+		}
+		for _, p := range points {
+			assert.InEpsilon(math.Abs(d), math.Sqrt(ell.SampsonDistance(p[0], p[1])), 1e-2)
+		}
+	}
+
+	x, y := ell.PointAt(1.0)
+	assert.InDelta(0, ell.SampsonDistance(x, y), 1e-12)
+	assert.True(math.IsInf(ell.SampsonDistance(1.0, 2.0), 1))
+}
+
+func TestClassify(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+	bx, by := ell.PointAt(1.0)
+
+	testCases := []struct {
+		x   float64
+		y   float64
+		exp int
+	}{
+		{1.0, 2.0, -1},
+		{1.5, 2.5, -1},
+		{bx, by, 0},
+		{10.0, 10.0, 1},
+		{-5.0, 2.0, 1},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.exp, ell.Classify(tc.x, tc.y, 1e-9))
+	}
+}
+
+func TestSignedDistance(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 6}
+	sin, cos := math.Sincos(ell.angle)
+
+	// c2 is the squared linear eccentricity
+	c2 := ell.a*ell.a - ell.b*ell.b
+
+	testCases := []struct {
+		u   float64
+		exp float64
+	}{
+		{5.0, 2.0},
+		{-4.0, 1.0},
+		{3.0, 0},
+		// inside points close to the vertex are closest to the vertex
+		{2.9, -0.1},
+		{-2.8, -0.2},
+		// inside points close to the center are closest to a point off the major axis
+		{0, -1.0},
+		{1.0, -ell.b * math.Sqrt(1-1.0/c2)},
+		{-2.0, -ell.b * math.Sqrt(1-4.0/c2)},
+	}
+
+	for _, tc := range testCases {
+		x, y := ell.x+tc.u*cos, ell.y+tc.u*sin
+		assert.InDelta(tc.exp, ell.SignedDistance(x, y), 1e-9)
+	}
+
+	// points off the major axis
+	for _, theta := range []float64{0.3, 1.0, 2.5, 4.0} {
+		px, py, nx, ny := ell.NormalAt(theta)
+		assert.InDelta(0.1, ell.SignedDistance(px+0.1*nx, py+0.1*ny), 1e-9)
+		assert.InDelta(-0.1, ell.SignedDistance(px-0.1*nx, py-0.1*ny), 1e-9)
+	}
+}
+
+func TestClosestPoint(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0}
+
+	testCases := []struct {
+		x  float64
+		y  float64
+		px float64
+		py float64
+	}{
+		{6.0, 2.0, 4.0, 2.0},
+		{-4.0, 2.0, -2.0, 2.0},
+		{1.0, 5.0, 1.0, 3.0},
+		{1.0, 2.5, 1.0, 3.0},
+		{1.0, 2.0, 1.0, 3.0},
+		{4.0, 2.0, 4.0, 2.0},
+	}
+
+	for _, tc := range testCases {
+		px, py := ell.ClosestPoint(tc.x, tc.y)
+		assert.InDelta(tc.px, px, 1e-9)
+		assert.InDelta(tc.py, py, 1e-9)
+	}
+
+	// points moved along the boundary normal have the boundary point as the closest point
+	ell = Ellipse{x: 1.0, y: 2.0, a: 1.0, b: 3.0, angle: math.Pi / 3}
+	for _, theta := range []float64{0.3, 1.0, 2.0, 4.0, 5.5} {
+		for _, d := range []float64{-0.2, 0.01, 0.5, 2.0} {
+			bx, by, nx, ny := ell.NormalAt(theta)
+			px, py := ell.ClosestPoint(bx+d*nx, by+d*ny)
+			assert.InDelta(bx, px, 1e-9)
+			assert.InDelta(by, py, 1e-9)
+			assert.InDelta(math.Abs(d), ell.distance(bx+d*nx, by+d*ny), 1e-9)
+		}
+	}
+
+	// circle
+	ell = Ellipse{a: 2.0, b: 2.0}
+	px, py := ell.ClosestPoint(3.0, 4.0)
+	assert.InDelta(1.2, px, 1e-9)
+	assert.InDelta(1.6, py, 1e-9)
+}
+
 func TestEccentricity(t *testing.T) {
 	assert := assert.New(t)
 
 	ell := Ellipse{a: 1.0, b: 3.0, angle: math.Pi}
 	ecc := ell.Eccentricity()
 	assert.NotZero(ecc)
+
+	testCases := []struct {
+		e   *Ellipse
+		exp float64
+	}{
+		{&Ellipse{a: 2.0, b: 2.0}, 0},
+		{&Ellipse{a: 5.0, b: 3.0}, 0.8},
+		{&Ellipse{a: 3.0, b: 5.0, angle: 1.0}, 0.8},
+	}
+
+	for _, tc := range testCases {
+		assert.InDelta(tc.exp, tc.e.Eccentricity(), 1e-12)
+	}
+}
+
+func TestShapeDescriptor(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 5.0, b: 3.0, angle: math.Pi / 6}
+	desc := ell.ShapeDescriptor()
+	assert.InDelta(15*math.Pi, desc[0], 1e-12)
+	assert.InDelta(0.8, desc[1], 1e-12)
+
+	// congruent ellipses have identical descriptors
+	congruent := []*Ellipse{
+		ell.Centered(),
+		ell.Canonical(),
+		ell.Rotate(1.0),
+		ell.RotateAboutPoint(-3.0, 4.0, 2.0),
+		{x: -7.0, y: 3.0, a: 3.0, b: 5.0, angle: 0.4},
+	}
+
+	for _, c := range congruent {
+		d := c.ShapeDescriptor()
+		assert.InDeltaSlice(desc[:], d[:], 1e-12)
+	}
+
+	// different shapes have different descriptors
+	assert.NotEqual(desc, (&Ellipse{a: 5.0, b: 2.0}).ShapeDescriptor())
+	assert.NotEqual(desc, (&Ellipse{a: 10.0, b: 6.0}).ShapeDescriptor())
+}
+
+func TestPerimeterExact(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []*Ellipse{
+		{a: 2.0, b: 2.0},
+		{x: 1.0, y: 1.0, a: 3.0, b: 1.0},
+		{a: 1.0, b: 3.0, angle: math.Pi / 3},
+		{a: 10.0, b: 0.5},
+	}
+
+	for _, ell := range testCases {
+		arc := ell.ArcLength(0, 2*math.Pi)
+		exact := ell.PerimeterExact()
+		assert.InDelta(arc, exact, 1e-9)
+		assert.True(math.Abs(arc-exact) <= math.Abs(arc-ell.Perimeter())+1e-12)
+	}
+
+	circle := &Ellipse{a: 2.0, b: 2.0}
+	assert.InDelta(4*math.Pi, circle.PerimeterExact(), 1e-12)
+}
+
+func TestPerimeterMethod(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 1.0, a: 3.0, b: 1.0, angle: math.Pi / 4}
+	assert.Equal(ell.PerimeterExact(), ell.PerimeterMethod(EllipticIntegral))
+
+	// the documented relative error bounds of the methods
+	testCases := []struct {
+		m   PerimeterAlgo
+		tol float64
+	}{
+		{Ramanujan1, 4.2e-3},
+		{Ramanujan2, 4.1e-4},
+		{EllipticIntegral, 1e-12},
+	}
+
+	// the approximation errors are largest for highly eccentric ellipses
+	for _, e := range []*Ellipse{ell, {a: 1000.0, b: 1.0}, {a: 1.0, b: 1e-9, angle: 0.3}} {
+		exact := e.PerimeterExact()
+		for _, tc := range testCases {
+			assert.InEpsilon(exact, e.PerimeterMethod(tc.m), tc.tol)
+		}
+	}
+
+	// Ramanujan's second approximation error for the highly eccentric ellipse is close to its bound
+	eccentric := &Ellipse{a: 1000.0, b: 1.0}
+	assert.True(math.Abs(eccentric.PerimeterMethod(Ramanujan2)/eccentric.PerimeterExact()-1) > 3e-4)
+
+	circle := &Ellipse{a: 2.0, b: 2.0}
+	for _, tc := range testCases {
+		assert.InDelta(4*math.Pi, circle.PerimeterMethod(tc.m), 1e-12)
+	}
+
+	assert.Panics(func() { ell.PerimeterMethod(PerimeterAlgo(-1)) })
+}
+
+func TestArcLength(t *testing.T) {
+	assert := assert.New(t)
+
+	circle := &Ellipse{x: 1.0, y: 2.0, a: 2.0, b: 2.0}
+	assert.InDelta(math.Pi, circle.ArcLength(0, math.Pi/2), 1e-9)
+	assert.InDelta(-math.Pi, circle.ArcLength(math.Pi/2, 0), 1e-9)
+	assert.InDelta(0, circle.ArcLength(1.0, 1.0), 1e-12)
+
+	// every quadrant of the ellipse has the same length
+	ell := &Ellipse{a: 3.0, b: 1.0, angle: math.Pi / 5}
+	for i := 0; i < 4; i++ {
+		t0 := float64(i) * math.Pi / 2
+		assert.InDelta(ell.PerimeterExact()/4, ell.ArcLength(t0, t0+math.Pi/2), 1e-9)
+	}
+
+	// arcs spanning several turns
+	assert.InDelta(3*ell.PerimeterExact(), ell.ArcLength(1.0, 1.0+6*math.Pi), 1e-9)
+	assert.InDelta(2*ell.PerimeterExact()+ell.ArcLength(0.5, 1.5), ell.ArcLength(0.5, 1.5+4*math.Pi), 1e-9)
+	assert.InDelta(1e6/(2*math.Pi)*circle.Perimeter(), circle.ArcLength(0, 1e6), 1e-3)
+
+	for _, tc := range [][2]float64{
+		{0, math.Inf(1)},
+		{math.Inf(-1), 0},
+		{math.NaN(), 1.0},
+		{0, math.NaN()},
+	} {
+		assert.True(math.IsNaN(ell.ArcLength(tc[0], tc[1])))
+	}
+}
+
+func TestIsDegenerate(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		e   *Ellipse
+		tol float64
+		exp bool
+	}{
+		{&Ellipse{a: 1.0, b: 1.0}, 1e-3, false},
+		{&Ellipse{a: 1.0, b: 1e-4}, 1e-3, true},
+		{&Ellipse{a: 1e-4, b: 1.0, angle: 1.0}, 1e-3, true},
+		{&Ellipse{a: 2.0, b: 1.0}, 0.5, false},
+		{&Ellipse{a: 2.0, b: 1.0}, 0.6, true},
+		{&Ellipse{a: 2.0, b: math.NaN()}, 1e-3, true},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.exp, tc.e.IsDegenerate(tc.tol))
+	}
+}
+
+func TestBoundaryBySpacing(t *testing.T) {
+	assert := assert.New(t)
+
+	// points on a circle are equidistant
+	r, n := 2.0, 12
+	circle := &Ellipse{x: 1.0, y: 1.0, a: r, b: r}
+	xys, err := circle.BoundaryBySpacing(2 * math.Pi * r / float64(n))
+	assert.NoError(err)
+	assert.Len(xys, n)
+	chord := 2 * r * math.Sin(math.Pi/float64(n))
+	for i := range xys {
+		p, q := xys[i], xys[(i+1)%len(xys)]
+		assert.InDelta(chord, math.Hypot(q.X-p.X, q.Y-p.Y), 1e-9)
+	}
+
+	// points on an ellipse are spaced by the same arc length
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+	xys, err = ell.BoundaryBySpacing(0.5)
+	assert.NoError(err)
+	assert.Len(xys, int(math.Ceil(ell.PerimeterExact()/0.5)))
+	prev := 0.0
+	for i, xy := range xys {
+		assert.InDelta(0, ell.Implicit(xy.X, xy.Y), 1e-9)
+		u, v := ell.local(xy.X, xy.Y)
+		if i > 0 {
+			theta := math.Atan2(v/ell.b, u/ell.a)
+			if theta < 0 {
+				theta += 2 * math.Pi
+			}
+			assert.InDelta(0.5, ell.ArcLength(prev, theta), 1e-9)
+			prev = theta
+		}
+	}
+
+	xys, err = ell.BoundaryBySpacing(100.0)
+	assert.NoError(err)
+	assert.Len(xys, 1)
+
+	// spacings yielding too many points are rejected
+	for _, spacing := range []float64{0, -1.0, math.NaN(), 1e-300, math.SmallestNonzeroFloat64, ell.Perimeter() / (maxSpacingPoints + 1)} {
+		xys, err := ell.BoundaryBySpacing(spacing)
+		assert.Error(err)
+		assert.Nil(xys)
+	}
+
+	xys, err = (&Ellipse{a: math.Inf(1), b: 1.0}).BoundaryBySpacing(1.0)
+	assert.Error(err)
+	assert.Nil(xys)
+}
+
+func TestNormalizedAngle(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		angle float64
+		exp   float64
+	}{
+		{0, 0},
+		{math.Pi / 4, math.Pi / 4},
+		{math.Pi, 0},
+		{-math.Pi / 4, 3 * math.Pi / 4},
+		{-5.6, -5.6 + 2*math.Pi},
+		{2*math.Pi + math.Pi/4, math.Pi / 4},
+		{5*math.Pi + math.Pi/4, math.Pi / 4},
+		{-1e-18, 0},
+	}
+
+	for _, tc := range testCases {
+		ell := Ellipse{a: 1.0, b: 3.0, angle: tc.angle}
+		angle := ell.NormalizedAngle()
+		assert.InDelta(tc.exp, angle, 1e-9)
+		assert.True(angle >= 0 && angle < math.Pi)
+	}
+}
+
+func TestCurvatureRadius(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		e   *Ellipse
+		min float64
+		max float64
+	}{
+		{&Ellipse{a: 2.0, b: 2.0}, 2.0, 2.0},
+		{&Ellipse{x: 1.0, y: 1.0, a: 4.0, b: 2.0}, 1.0, 8.0},
+		{&Ellipse{a: 2.0, b: 4.0, angle: math.Pi / 3}, 1.0, 8.0},
+		{&Ellipse{a: 3.0, b: 1.0}, 1.0 / 3.0, 9.0},
+	}
+
+	for _, tc := range testCases {
+		assert.InDelta(tc.min, tc.e.MinCurvatureRadius(), 1e-12)
+		assert.InDelta(tc.max, tc.e.MaxCurvatureRadius(), 1e-12)
+	}
+}
+
+func TestInertiaTensor(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 6}
+
+	ixx, iyy := ell.SecondMoments()
+	assert.InDelta(3*math.Pi/4, ixx, 1e-12)
+	assert.InDelta(27*math.Pi/4, iyy, 1e-12)
+
+	tensor := ell.InertiaTensor()
+	assert.Equal(tensor.At(0, 1), tensor.At(1, 0))
+
+	var eig mat.EigenSym
+	assert.True(eig.Factorize(mat.NewSymDense(2, []float64{tensor.At(0, 0), tensor.At(0, 1), tensor.At(1, 0), tensor.At(1, 1)}), true))
+	vals := eig.Values(nil)
+	assert.InDelta(ixx, vals[0], 1e-9)
+	assert.InDelta(iyy, vals[1], 1e-9)
+
+	// the eigenvector of ixx is aligned with the semi-axis a
+	var vecs mat.Dense
+	eig.VectorsTo(&vecs)
+	sin, cos := math.Sincos(ell.angle)
+	assert.InDelta(1.0, math.Abs(vecs.At(0, 0)*cos+vecs.At(1, 0)*sin), 1e-9)
+	assert.InDelta(1.0, math.Abs(-vecs.At(0, 1)*sin+vecs.At(1, 1)*cos), 1e-9)
+
+	// circle tensor is isotropic
+	circle := &Ellipse{a: 2.0, b: 2.0, angle: 1.0}
+	tensor = circle.InertiaTensor()
+	assert.InDelta(4*math.Pi, tensor.At(0, 0), 1e-12)
+	assert.InDelta(4*math.Pi, tensor.At(1, 1), 1e-12)
+	assert.InDelta(0, tensor.At(0, 1), 1e-12)
+}
+
+func TestInscribedRect(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0}
+	minX, minY, maxX, maxY := ell.InscribedRect()
+	assert.InDelta(1.0-3.0/math.Sqrt2, minX, 1e-12)
+	assert.InDelta(2.0-1.0/math.Sqrt2, minY, 1e-12)
+	assert.InDelta(1.0+3.0/math.Sqrt2, maxX, 1e-12)
+	assert.InDelta(2.0+1.0/math.Sqrt2, maxY, 1e-12)
+
+	// rotating the ellipse by a quarter-turn swaps the rectangle dimensions
+	ell.angle = math.Pi / 2
+	minX, minY, maxX, maxY = ell.InscribedRect()
+	assert.InDelta(2.0/math.Sqrt2, maxX-minX, 1e-9)
+	assert.InDelta(6.0/math.Sqrt2, maxY-minY, 1e-9)
+
+	testCases := []*Ellipse{
+		{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3},
+		{x: -1.0, y: 0, a: 1.0, b: 4.0, angle: 0.2},
+		{x: 0, y: 0, a: 2.0, b: 2.0, angle: 1.0},
+	}
+
+	for _, e := range testCases {
+		minX, minY, maxX, maxY := e.InscribedRect()
+		w, h := (maxX-minX)/2, (maxY-minY)/2
+		assert.InDelta(e.x, (minX+maxX)/2, 1e-12)
+		assert.InDelta(e.y, (minY+maxY)/2, 1e-12)
+
+		// the rectangle corners lie inside the ellipse and two of them touch its boundary
+		touching := 0
+		for _, corner := range []plotter.XY{{X: minX, Y: minY}, {X: minX, Y: maxY}, {X: maxX, Y: minY}, {X: maxX, Y: maxY}} {
+			v := e.Implicit(corner.X, corner.Y)
+			assert.True(v <= 1e-9)
+			if math.Abs(v) <= 1e-9 {
+				touching++
+			}
+		}
+		assert.True(touching >= 2)
+
+		// no other centered rectangle touching the ellipse has larger area
+		for i := 1; i < 1000; i++ {
+			tw := w * 2 * float64(i) / 1000
+			// the largest height of the rectangle of half-width tw
+			fits := func(th float64) bool {
+				return e.Implicit(e.x+tw, e.y+th) <= 0 && e.Implicit(e.x+tw, e.y-th) <= 0
+			}
+			lo, hi := 0.0, e.Diameter()
+			for j := 0; j < 60; j++ {
+				mid := (lo + hi) / 2
+				if fits(mid) {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			assert.True(tw*lo <= w*h+1e-9)
+		}
+	}
+}
+
+func TestExtremePoints(t *testing.T) {
+	assert := assert.New(t)
+
+	// the extreme points of 45 degrees rotated ellipse are off its axes
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 4}
+	top, bottom, left, right := ell.ExtremePoints()
+
+	// x^2/9 + y^2 = 1 rotated by 45 degrees has the extreme coordinates at +-sqrt(5)
+	// which are attained at the points shifted by +-4/sqrt(5) along the other axis
+	ext, off := math.Sqrt(5), 4/math.Sqrt(5)
+	assert.InDelta(1.0+off, top.X, 1e-12)
+	assert.InDelta(2.0+ext, top.Y, 1e-12)
+	assert.InDelta(1.0-off, bottom.X, 1e-12)
+	assert.InDelta(2.0-ext, bottom.Y, 1e-12)
+	assert.InDelta(1.0-ext, left.X, 1e-12)
+	assert.InDelta(2.0-off, left.Y, 1e-12)
+	assert.InDelta(1.0+ext, right.X, 1e-12)
+	assert.InDelta(2.0+off, right.Y, 1e-12)
+
+	testCases := []*Ellipse{
+		ell,
+		{a: 2.0, b: 1.0},
+		{x: -1.0, y: 3.0, a: 1.0, b: 4.0, angle: 2.0},
+		{a: 2.0, b: 5.0, angle: -0.3},
+	}
+
+	for _, e := range testCases {
+		top, bottom, left, right := e.ExtremePoints()
+		minX, minY, maxX, maxY := e.BoundingBox()
+		assert.InDelta(maxY, top.Y, 1e-12)
+		assert.InDelta(minY, bottom.Y, 1e-12)
+		assert.InDelta(minX, left.X, 1e-12)
+		assert.InDelta(maxX, right.X, 1e-12)
+		for _, p := range []plotter.XY{top, bottom, left, right} {
+			assert.InDelta(0, e.Implicit(p.X, p.Y), 1e-12)
+		}
+	}
+}
+
+func TestTightRect(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi/6 + math.Pi}
+
+	width, height, center, angle := ell.TightRect()
+	assert.Equal(6.0, width)
+	assert.Equal(2.0, height)
+	assert.Equal(plotter.XY{X: 1.0, Y: 2.0}, center)
+	assert.InDelta(math.Pi/6, angle, 1e-12)
+
+	// the ellipse touches the rectangle sides at its vertices
+	sin, cos := math.Sincos(angle)
+	for _, c := range [][2]float64{{width / 2, 0}, {-width / 2, 0}, {0, height / 2}, {0, -height / 2}} {
+		x := center.X + c[0]*cos - c[1]*sin
+		y := center.Y + c[0]*sin + c[1]*cos
+		assert.InDelta(0, ell.Implicit(x, y), 1e-12)
+	}
+
+	// the rectangle is smaller than the axis aligned bounding box
+	minX, minY, maxX, maxY := ell.BoundingBox()
+	assert.True(width*height < (maxX-minX)*(maxY-minY))
+}
+
+func TestBoundingCircle(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []*Ellipse{
+		{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3},
+		{x: -1.0, y: 0, a: 1.0, b: 4.0, angle: 0.2},
+		{x: 0, y: 0, a: 2.0, b: 2.0, angle: 1.0, scale: 5.99},
+	}
+
+	for _, ell := range testCases {
+		c := ell.BoundingCircle()
+		assert.Equal(ell.x, c.x)
+		assert.Equal(ell.y, c.y)
+		assert.Equal(math.Max(ell.a, ell.b), c.a)
+		assert.Equal(c.a, c.b)
+		assert.Zero(c.angle)
+		assert.Zero(c.scale)
+		assert.Equal(ell.Diameter(), c.Diameter())
+
+		xys := ell.boundary(100)
+		for _, p := range xys {
+			assert.True(c.Implicit(p.X, p.Y) <= 1e-9)
+		}
+	}
+}
+
+func TestDiameterWidth(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		a     float64
+		b     float64
+		diam  float64
+		width float64
+	}{
+		{1.0, 3.0, 6.0, 2.0},
+		{3.0, 1.0, 6.0, 2.0},
+		{2.0, 2.0, 4.0, 4.0},
+	}
+
+	for _, tc := range testCases {
+		ell := Ellipse{a: tc.a, b: tc.b, angle: math.Pi / 4}
+		assert.Equal(tc.diam, ell.Diameter())
+		assert.Equal(tc.width, ell.Width())
+	}
+}
+
+func TestCenterDistance(t *testing.T) {
+	assert := assert.New(t)
+
+	e1 := &Ellipse{x: 1.0, y: 1.0, a: 1.0, b: 3.0}
+	e2 := &Ellipse{x: 4.0, y: 5.0, a: 2.0, b: 1.0, angle: math.Pi}
+
+	assert.Equal(5.0, e1.CenterDistance(e2))
+	assert.Equal(5.0, e2.CenterDistance(e1))
+	assert.Zero(e1.CenterDistance(e1))
+}
+
+func TestClosestBoundaryPair(t *testing.T) {
+	assert := assert.New(t)
+
+	c1 := &Ellipse{x: 1.0, y: 1.0, a: 1.0, b: 1.0}
+	c2 := &Ellipse{x: 5.0, y: 4.0, a: 2.0, b: 2.0}
+
+	pA, pB, dist := c1.ClosestBoundaryPair(c2, 20)
+	assert.InDelta(2.0, dist, 1e-6)
+	assert.InDelta(math.Hypot(pB.X-pA.X, pB.Y-pA.Y), dist, 1e-12)
+	// the closest points lie on the line joining the centers
+	assert.InDelta(1.0+0.8, pA.X, 1e-6)
+	assert.InDelta(1.0+0.6, pA.Y, 1e-6)
+	assert.InDelta(5.0-1.6, pB.X, 1e-6)
+	assert.InDelta(4.0-1.2, pB.Y, 1e-6)
+
+	// both points lie on the ellipse boundaries
+	e1 := &Ellipse{x: -2.0, y: 0, a: 3.0, b: 1.0, angle: math.Pi / 4}
+	e2 := &Ellipse{x: 4.0, y: -1.0, a: 1.0, b: 2.0}
+	pA, pB, dist = e1.ClosestBoundaryPair(e2, 50)
+	assert.InDelta(0, e1.Implicit(pA.X, pA.Y), 1e-9)
+	assert.InDelta(0, e2.Implicit(pB.X, pB.Y), 1e-9)
+	assert.InDelta(e2.distance(pA.X, pA.Y), dist, 1e-6)
+	assert.InDelta(e1.distance(pB.X, pB.Y), dist, 1e-6)
+}
+
+func TestMajorAxisAngleTo(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 1.0, a: 3.0, b: 1.0, angle: math.Pi / 6}
+
+	testCases := []struct {
+		other *Ellipse
+		exp   float64
+	}{
+		// parallel
+		{&Ellipse{x: 5.0, y: -1.0, a: 2.0, b: 1.0, angle: math.Pi / 6}, 0},
+		{&Ellipse{a: 2.0, b: 1.0, angle: math.Pi/6 + math.Pi}, 0},
+		{&Ellipse{a: 1.0, b: 2.0, angle: math.Pi/6 - math.Pi/2}, 0},
+		// perpendicular
+		{&Ellipse{a: 2.0, b: 1.0, angle: math.Pi/6 + math.Pi/2}, math.Pi / 2},
+		{&Ellipse{a: 1.0, b: 2.0, angle: math.Pi / 6}, math.Pi / 2},
+		{&Ellipse{a: 2.0, b: 1.0, angle: math.Pi/6 - math.Pi/4}, math.Pi / 4},
+		{&Ellipse{a: 2.0, b: 1.0, angle: math.Pi - 0.1}, math.Pi/6 + 0.1},
+	}
+
+	for _, tc := range testCases {
+		assert.InDelta(tc.exp, ell.MajorAxisAngleTo(tc.other), 1e-12)
+		assert.InDelta(tc.exp, tc.other.MajorAxisAngleTo(ell), 1e-12)
+	}
+}
+
+func TestFoci(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		e  *Ellipse
+		f1 plotter.XY
+		f2 plotter.XY
+	}{
+		{&Ellipse{x: 1.0, y: 1.0, a: 5.0, b: 3.0}, plotter.XY{X: 5.0, Y: 1.0}, plotter.XY{X: -3.0, Y: 1.0}},
+		{&Ellipse{a: 3.0, b: 5.0}, plotter.XY{X: 0, Y: 4.0}, plotter.XY{X: 0, Y: -4.0}},
+		{&Ellipse{a: 5.0, b: 3.0, angle: math.Pi / 2}, plotter.XY{X: 0, Y: 4.0}, plotter.XY{X: 0, Y: -4.0}},
+		{&Ellipse{x: 2.0, y: 3.0, a: 2.0, b: 2.0}, plotter.XY{X: 2.0, Y: 3.0}, plotter.XY{X: 2.0, Y: 3.0}},
+	}
+
+	for _, tc := range testCases {
+		f1, f2 := tc.e.Foci()
+		assert.InDelta(tc.f1.X, f1.X, 1e-9)
+		assert.InDelta(tc.f1.Y, f1.Y, 1e-9)
+		assert.InDelta(tc.f2.X, f2.X, 1e-9)
+		assert.InDelta(tc.f2.Y, f2.Y, 1e-9)
+	}
+}
+
+func TestSemiLatusRectum(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.InDelta(1.8, (&Ellipse{a: 5.0, b: 3.0}).SemiLatusRectum(), 1e-9)
+	assert.InDelta(1.8, (&Ellipse{a: 3.0, b: 5.0}).SemiLatusRectum(), 1e-9)
+	assert.InDelta(2.0, (&Ellipse{a: 2.0, b: 2.0}).SemiLatusRectum(), 1e-9)
+}
+
+func TestApproxEqual(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+
+	testCases := []struct {
+		other *Ellipse
+		exp   bool
+	}{
+		{&Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}, true},
+		{&Ellipse{x: 1.0 + 1e-10, y: 2.0 - 1e-10, a: 3.0, b: 1.0, angle: math.Pi/3 + 1e-10}, true},
+		{&Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi/3 + math.Pi}, true},
+		{&Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi/3 - 2*math.Pi}, true},
+		{&Ellipse{x: 1.1, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}, false},
+		{&Ellipse{x: 1.0, y: 2.1, a: 3.0, b: 1.0, angle: math.Pi / 3}, false},
+		{&Ellipse{x: 1.0, y: 2.0, a: 3.1, b: 1.0, angle: math.Pi / 3}, false},
+		{&Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.1, angle: math.Pi / 3}, false},
+		{&Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 2}, false},
+		// swapped axes with the angle shifted by pi/2 describe the same ellipse
+		{&Ellipse{x: 1.0, y: 2.0, a: 1.0, b: 3.0, angle: math.Pi/3 + math.Pi/2}, true},
+		{&Ellipse{x: 1.0, y: 2.0, a: 1.0, b: 3.0, angle: math.Pi/3 - math.Pi/2}, true},
+		{&Ellipse{x: 1.0, y: 2.0, a: 1.0, b: 3.0, angle: math.Pi / 3}, false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.exp, ell.ApproxEqual(tc.other, 1e-9))
+		assert.Equal(tc.exp, tc.other.ApproxEqual(ell, 1e-9))
+	}
+
+	// angles close to 0 and pi are close to each other
+	e1 := &Ellipse{a: 3.0, b: 1.0, angle: 1e-10}
+	e2 := &Ellipse{a: 3.0, b: 1.0, angle: math.Pi - 1e-10}
+	assert.True(e1.ApproxEqual(e2, 1e-9))
+
+	e1, err := New(0, 0, 5, 1, 0)
+	assert.NoError(err)
+	e2, err = New(0, 0, 1, 5, math.Pi/2)
+	assert.NoError(err)
+	assert.True(e1.ApproxEqual(e2, 1e-9))
+	assert.True(e2.ApproxEqual(e1, 1e-9))
+
+	// rotation angle of circles is ignored
+	c1 := &Ellipse{x: 1.0, y: 1.0, a: 2.0, b: 2.0, angle: 0.3}
+	c2 := &Ellipse{x: 1.0, y: 1.0, a: 2.0, b: 2.0 + 1e-10, angle: 1.2}
+	assert.True(c1.ApproxEqual(c2, 1e-9))
+	assert.False(c1.ApproxEqual(&Ellipse{x: 1.0, y: 1.0, a: 2.0, b: 1.9, angle: 1.2}, 1e-9))
+}
+
+func TestLabelPoints(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 6.0, b: 2.0, angle: math.Pi / 4}
+	data := gaussData(500, 1.0, 2.0, 7)
+
+	labels, err := ell.LabelPoints(data)
+	assert.NoError(err)
+	assert.Len(labels, 500)
+
+	inside := 0
+	for i, label := range labels {
+		assert.Equal(ell.Contains(data.At(i, 0), data.At(i, 1)), label)
+		if label {
+			inside++
+		}
+	}
+	assert.True(inside > 0 && inside < 500)
+
+	count, err := ell.CountInside(data)
+	assert.NoError(err)
+	assert.Equal(inside, count)
+
+	labels, err = ell.LabelPoints(mat.NewDense(2, 1, []float64{1.0, 2.0}))
+	assert.Error(err)
+	assert.Nil(labels)
+
+	count, err = ell.CountInside(mat.NewDense(2, 1, []float64{1.0, 2.0}))
+	assert.Error(err)
+	assert.Equal(0, count)
+}
+
+func TestBoundaryFractionInside(t *testing.T) {
+	assert := assert.New(t)
+
+	large := &Ellipse{x: 1.0, y: 1.0, a: 5.0, b: 3.0, angle: math.Pi / 6}
+	small := &Ellipse{x: 1.5, y: 1.0, a: 2.0, b: 1.0, angle: 1.0}
+	far := &Ellipse{x: 20.0, y: 20.0, a: 2.0, b: 1.0}
+
+	assert.Equal(1.0, small.BoundaryFractionInside(large, 100))
+	assert.Equal(0.0, large.BoundaryFractionInside(small, 100))
+	assert.Equal(0.0, small.BoundaryFractionInside(far, 100))
+	assert.Equal(0.0, far.BoundaryFractionInside(small, 100))
+
+	// a third of the circle boundary lies inside the circle of the same radius centered on its boundary
+	c1 := &Ellipse{x: 2.0, a: 2.0, b: 2.0}
+	c2 := &Ellipse{a: 2.0, b: 2.0}
+	assert.InDelta(1.0/3.0, c1.BoundaryFractionInside(c2, 6000), 1e-3)
+	// the right half of the unit circle lies inside the circle whose boundary passes through [0,-1] and [0,1]
+	c3 := &Ellipse{a: 1.0, b: 1.0}
+	c4 := &Ellipse{x: 1.0, a: math.Sqrt2, b: math.Sqrt2}
+	assert.InDelta(0.5, c3.BoundaryFractionInside(c4, 6000), 1e-3)
 }
 
 func TestString(t *testing.T) {
@@ -116,3 +1330,24 @@ func TestXYFromDense(t *testing.T) {
 		assert.NotNil(xy)
 	}
 }
+
+func TestXYFromDenseErr(t *testing.T) {
+	assert := assert.New(t)
+
+	m := mat.NewDense(3, 2, []float64{1.0, 2.0, 3.0, 4.0, 5.0, 6.0})
+	xy, err := XYFromDenseErr(m)
+	assert.NoError(err)
+	assert.Equal(XYFromDense(m), xy)
+
+	testCases := []*mat.Dense{
+		nil,
+		mat.NewDense(5, 1, nil),
+		{},
+	}
+
+	for _, tc := range testCases {
+		xy, err := XYFromDenseErr(tc)
+		assert.Error(err)
+		assert.Nil(xy)
+	}
+}
@@ -0,0 +1,252 @@
+package ellipse
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// boundingSize is the number of boundary points sampled from each ellipse enclosed by BoundingEllipse
+	boundingSize = 100
+	// boundingTol is the convergence tolerance used by BoundingEllipse
+	boundingTol = 1e-6
+	// mveeMaxIter is the maximum number of iterations of the Khachiyan algorithm
+	mveeMaxIter = 10000
+)
+
+// BoundingEllipse returns the minimum area ellipse which encloses all of the given ellipses.
+// The enclosing ellipse is computed from the points sampled from the ellipses boundaries.
+// It returns error if ellipses is empty or if the enclosing ellipse could not be computed.
+func BoundingEllipse(ellipses []*Ellipse) (*Ellipse, error) {
+	if len(ellipses) == 0 {
+		return nil, fmt.Errorf("Empty ellipses")
+	}
+
+	data := mat.NewDense(len(ellipses)*boundingSize, 2, nil)
+	for i, e := range ellipses {
+		for j, p := range e.boundary(boundingSize) {
+			data.Set(i*boundingSize+j, 0, p.X)
+			data.Set(i*boundingSize+j, 1, p.Y)
+		}
+	}
+
+	return mvee(data, boundingTol)
+}
+
+// NewEnclosing creates new Ellipse which is the minimum area ellipse enclosing all the points
+// stored in the first two columns of data. The ellipse is computed using Khachiyan algorithm
+// which iterates until the change of the point weights drops below tol convergence tolerance.
+// It returns error if tol is not positive, data has less than 2 columns or less than 3 rows
+// or if the enclosing ellipse could not be computed e.g. when all the points are collinear.
+func NewEnclosing(data mat.Matrix, tol float64) (*Ellipse, error) {
+	if tol <= 0 {
+		return nil, fmt.Errorf("Invalid tolerance: %v", tol)
+	}
+
+	if _, cols := data.Dims(); cols < 2 {
+		return nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+
+	return mvee(data, tol)
+}
+
+// NewSteinerInellipse creates new Ellipse which is the Steiner inellipse of the triangle with vertices [ax,ay], [bx,by] and [cx,cy]
+// i.e. the unique ellipse inscribed in the triangle which touches the triangle edges at their midpoints.
+// The inellipse is centered at the triangle centroid g and its boundary consists of the points p which satisfy
+// d^T * s^-1 * d = 1, where d = p - g and s = 1/6 * sum((v-g)*(v-g)^T) over the triangle vertices v.
+// It returns error if the vertices are collinear.
+//
+// For more information see: https://en.wikipedia.org/wiki/Steiner_inellipse
+func NewSteinerInellipse(ax, ay, bx, by, cx, cy float64) (*Ellipse, error) {
+	if (bx-ax)*(cy-ay)-(by-ay)*(cx-ax) == 0 {
+		return nil, fmt.Errorf("Invalid triangle: collinear vertices")
+	}
+
+	// triangle centroid
+	gx, gy := (ax+bx+cx)/3, (ay+by+cy)/3
+
+	var sxx, sxy, syy float64
+	for _, v := range [3][2]float64{{ax, ay}, {bx, by}, {cx, cy}} {
+		dx, dy := v[0]-gx, v[1]-gy
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	shape := mat.NewSymDense(2, []float64{sxx / 6, sxy / 6, sxy / 6, syy / 6})
+
+	e, err := newFromShape(gx, gy, shape)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid triangle: %v", err)
+	}
+
+	return e, nil
+}
+
+// SeparatingEllipse returns the ellipse which encloses the points stored in the first two columns of inside
+// while excluding as many of the points stored in the first two columns of outside as possible.
+// The ellipse is computed by the following heuristic: first the minimum area ellipse enclosing all the inside points
+// is computed, then the ellipse is shrunk about its center, keeping its shape and orientation, to the size
+// which minimizes the total number of the inside points left out and the outside points enclosed.
+// The candidate sizes are the ones at which the ellipse passes through one of the inside points
+// and the largest ellipse is returned if several sizes misclassify the same number of points.
+// The outside points are not guaranteed to be excluded: the heuristic only trades them off against the inside points.
+// It returns error if either inside or outside has less than 2 columns, inside has less than 3 rows
+// or if the enclosing ellipse of the inside points could not be computed e.g. when all the points are collinear.
+func SeparatingEllipse(inside, outside mat.Matrix) (*Ellipse, error) {
+	in, cols := inside.Dims()
+	if cols < 2 {
+		return nil, fmt.Errorf("Invalid number of inside data columns: %d", cols)
+	}
+
+	out, cols := outside.Dims()
+	if cols < 2 {
+		return nil, fmt.Errorf("Invalid number of outside data columns: %d", cols)
+	}
+
+	e, err := mvee(inside, boundingTol)
+	if err != nil {
+		return nil, err
+	}
+
+	// squared normalized radii of the points: the point lies on the ellipse scaled by sqrt(radius)
+	inRadii := make([]float64, in)
+	for i := range inRadii {
+		inRadii[i] = e.Implicit(inside.At(i, 0), inside.At(i, 1)) + 1
+	}
+	sort.Float64s(inRadii)
+
+	outRadii := make([]float64, out)
+	for i := range outRadii {
+		outRadii[i] = e.Implicit(outside.At(i, 0), outside.At(i, 1)) + 1
+	}
+	sort.Float64s(outRadii)
+
+	// count returns the number of radii which are at most r
+	count := func(radii []float64, r float64) int {
+		return sort.Search(len(radii), func(i int) bool { return radii[i] > r })
+	}
+
+	// walk the candidate sizes from the largest one so that ties are resolved in favour of larger ellipses
+	best, minErrs := inRadii[in-1], out+1
+	for i := in - 1; i >= 0; i-- {
+		r := inRadii[i]
+		if errs := in - count(inRadii, r) + count(outRadii, r); errs < minErrs {
+			best, minErrs = r, errs
+		}
+	}
+
+	if best < 1 {
+		e.a *= math.Sqrt(best)
+		e.b *= math.Sqrt(best)
+	}
+
+	return e, nil
+}
+
+// mvee returns the minimum volume enclosing ellipse of the points stored in the first two columns of data
+// computed using Khachiyan algorithm with tol convergence tolerance.
+// The axes of the returned ellipse are scaled so that the ellipse passes through the most distant point.
+//
+// For more information see: M. J. Todd, E. A. Yildirim: On Khachiyan's Algorithm for the Computation of Minimum Volume Enclosing Ellipsoids
+func mvee(data mat.Matrix, tol float64) (*Ellipse, error) {
+	n, _ := data.Dims()
+	if n < 3 {
+		return nil, fmt.Errorf("Insufficient number of points: %d", n)
+	}
+
+	// dimension of the points
+	d := 2.0
+
+	u := make([]float64, n)
+	for i := range u {
+		u[i] = 1 / float64(n)
+	}
+	prev := make([]float64, n)
+
+	// the points are lifted into homogeneous coordinates q = [x, y, 1]
+	x := mat.NewSymDense(3, nil)
+	var xinv mat.Dense
+	for iter := 0; iter < mveeMaxIter; iter++ {
+		// x = q * diag(u) * q^T
+		var sxx, sxy, syy, sx, sy float64
+		for j := 0; j < n; j++ {
+			px, py := data.At(j, 0), data.At(j, 1)
+			sxx += u[j] * px * px
+			sxy += u[j] * px * py
+			syy += u[j] * py * py
+			sx += u[j] * px
+			sy += u[j] * py
+		}
+		x.SetSym(0, 0, sxx)
+		x.SetSym(0, 1, sxy)
+		x.SetSym(0, 2, sx)
+		x.SetSym(1, 1, syy)
+		x.SetSym(1, 2, sy)
+		x.SetSym(2, 2, floats.Sum(u))
+		if err := xinv.Inverse(x); err != nil {
+			return nil, fmt.Errorf("Could not compute enclosing ellipse: %v", err)
+		}
+
+		// find the point with the largest Mahalanobis distance
+		maxJ, maxM := 0, math.Inf(-1)
+		for j := 0; j < n; j++ {
+			qj := [3]float64{data.At(j, 0), data.At(j, 1), 1}
+			m := 0.0
+			for r := 0; r < 3; r++ {
+				for c := 0; c < 3; c++ {
+					m += qj[r] * xinv.At(r, c) * qj[c]
+				}
+			}
+			if m > maxM {
+				maxJ, maxM = j, m
+			}
+		}
+
+		step := (maxM - d - 1) / ((d + 1) * (maxM - 1))
+		copy(prev, u)
+		floats.Scale(1-step, u)
+		u[maxJ] += step
+
+		if floats.Distance(u, prev, 2) < tol {
+			break
+		}
+	}
+
+	// ellipse center is the u weighted mean of the points
+	var cx, cy float64
+	for j := 0; j < n; j++ {
+		cx += u[j] * data.At(j, 0)
+		cy += u[j] * data.At(j, 1)
+	}
+
+	// shape matrix: d * (p * diag(u) * p^T - c * c^T)
+	var sxx, sxy, syy float64
+	for j := 0; j < n; j++ {
+		dx, dy := data.At(j, 0)-cx, data.At(j, 1)-cy
+		sxx += u[j] * dx * dx
+		sxy += u[j] * dx * dy
+		syy += u[j] * dy * dy
+	}
+	shape := mat.NewSymDense(2, []float64{d * sxx, d * sxy, d * sxy, d * syy})
+
+	e, err := newFromShape(cx, cy, shape)
+	if err != nil {
+		return nil, err
+	}
+
+	// scale the ellipse so that it passes through the most distant point
+	maxV := 0.0
+	for j := 0; j < n; j++ {
+		if v := e.Implicit(data.At(j, 0), data.At(j, 1)) + 1; v > maxV {
+			maxV = v
+		}
+	}
+	e.a *= math.Sqrt(maxV)
+	e.b *= math.Sqrt(maxV)
+
+	return e, nil
+}
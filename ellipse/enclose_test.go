@@ -0,0 +1,199 @@
+package ellipse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestBoundingEllipse(t *testing.T) {
+	assert := assert.New(t)
+
+	ellipses := []*Ellipse{
+		{x: 0, y: 0, a: 2.0, b: 1.0},
+		{x: 5.0, y: 1.0, a: 1.0, b: 3.0, angle: math.Pi / 4},
+		{x: 2.0, y: -4.0, a: 1.0, b: 1.0},
+	}
+
+	bound, err := BoundingEllipse(ellipses)
+	assert.NoError(err)
+	assert.NotNil(bound)
+
+	for _, ell := range ellipses {
+		for _, p := range ell.boundary(50) {
+			assert.True(bound.Implicit(p.X, p.Y) <= 1e-9)
+		}
+	}
+
+	// bounding ellipse of a single ellipse is the ellipse itself
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 4.0, b: 1.0, angle: math.Pi / 6}
+	bound, err = BoundingEllipse([]*Ellipse{ell})
+	assert.NoError(err)
+	assert.InDelta(ell.x, bound.x, 1e-3)
+	assert.InDelta(ell.y, bound.y, 1e-3)
+	assert.InDelta(ell.a, bound.a, 1e-3)
+	assert.InDelta(ell.b, bound.b, 1e-3)
+	assert.InDelta(ell.angle, bound.angle, 1e-3)
+
+	bound, err = BoundingEllipse(nil)
+	assert.Error(err)
+	assert.Nil(bound)
+}
+
+func TestNewEnclosing(t *testing.T) {
+	assert := assert.New(t)
+
+	// vertices of an axis aligned ellipse and points inside it
+	data := mat.NewDense(6, 2, []float64{
+		4.0, 1.0,
+		-2.0, 1.0,
+		1.0, 2.0,
+		1.0, 0,
+		1.5, 1.2,
+		0, 0.8,
+	})
+
+	ell, err := NewEnclosing(data, 1e-9)
+	assert.NoError(err)
+	assert.InDelta(1.0, ell.x, 1e-3)
+	assert.InDelta(1.0, ell.y, 1e-3)
+	assert.InDelta(3.0, ell.a, 1e-3)
+	assert.InDelta(1.0, ell.b, 1e-3)
+	assert.InDelta(0, ell.NormalizedAngle(), 1e-3)
+
+	rows, _ := data.Dims()
+	for i := 0; i < rows; i++ {
+		assert.True(ell.Implicit(data.At(i, 0), data.At(i, 1)) <= 1e-9)
+	}
+
+	data = gaussData(100, 1.0, 2.0, 1)
+	ell, err = NewEnclosing(data, 1e-6)
+	assert.NoError(err)
+	for i := 0; i < 100; i++ {
+		assert.True(ell.Implicit(data.At(i, 0), data.At(i, 1)) <= 1e-9)
+	}
+
+	testCases := []struct {
+		m   *mat.Dense
+		tol float64
+	}{
+		{data, 0},
+		{mat.NewDense(3, 1, []float64{1.0, 2.0, 3.0}), 1e-6},
+		{mat.NewDense(2, 2, []float64{1.0, 2.0, 3.0, 4.0}), 1e-6},
+		{mat.NewDense(3, 2, []float64{1.0, 1.0, 2.0, 2.0, 3.0, 3.0}), 1e-6},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewEnclosing(tc.m, tc.tol)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestSeparatingEllipse(t *testing.T) {
+	assert := assert.New(t)
+
+	inside := gaussData(200, 0, 0, 1)
+	outside := gaussData(200, 15.0, -15.0, 2)
+
+	ell, err := SeparatingEllipse(inside, outside)
+	assert.NoError(err)
+
+	count, err := ell.CountInside(inside)
+	assert.NoError(err)
+	assert.True(count >= 190)
+
+	count, err = ell.CountInside(outside)
+	assert.NoError(err)
+	assert.True(count <= 10)
+
+	// a few outside points in the middle of the inside cluster do not shrink the ellipse
+	center := mat.NewDense(3, 2, []float64{0, 0, 0.1, 0.1, -0.1, 0.1})
+	ell, err = SeparatingEllipse(inside, center)
+	assert.NoError(err)
+	count, err = ell.CountInside(inside)
+	assert.NoError(err)
+	assert.Equal(200, count)
+
+	// outside points surrounding the inside cluster shrink the ellipse
+	enclosing, err := NewEnclosing(inside, boundingTol)
+	assert.NoError(err)
+	shrunk, err := New(enclosing.x, enclosing.y, 0.95*enclosing.a, 0.95*enclosing.b, enclosing.angle)
+	assert.NoError(err)
+	pts := shrunk.BoundaryMatrix(400)
+	ell, err = SeparatingEllipse(inside, pts)
+	assert.NoError(err)
+	assert.True(ell.Area() < enclosing.Area())
+	count, err = ell.CountInside(pts)
+	assert.NoError(err)
+	assert.Equal(0, count)
+
+	testCases := []struct {
+		in  *mat.Dense
+		out *mat.Dense
+	}{
+		{mat.NewDense(3, 1, []float64{1.0, 2.0, 3.0}), outside},
+		{inside, mat.NewDense(3, 1, []float64{1.0, 2.0, 3.0})},
+		{mat.NewDense(2, 2, []float64{1.0, 2.0, 3.0, 4.0}), outside},
+		{mat.NewDense(3, 2, []float64{1.0, 1.0, 2.0, 2.0, 3.0, 3.0}), outside},
+	}
+
+	for _, tc := range testCases {
+		ell, err := SeparatingEllipse(tc.in, tc.out)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestNewSteinerInellipse(t *testing.T) {
+	assert := assert.New(t)
+
+	// equilateral triangle with circumradius 2 centered at [1,2]
+	var v [3][2]float64
+	for i := range v {
+		sin, cos := math.Sincos(math.Pi/2 + 2*math.Pi*float64(i)/3)
+		v[i] = [2]float64{1.0 + 2*cos, 2.0 + 2*sin}
+	}
+
+	ell, err := NewSteinerInellipse(v[0][0], v[0][1], v[1][0], v[1][1], v[2][0], v[2][1])
+	assert.NoError(err)
+	assert.InDelta(1.0, ell.x, 1e-9)
+	assert.InDelta(2.0, ell.y, 1e-9)
+	assert.InDelta(1.0, ell.a, 1e-9)
+	assert.InDelta(1.0, ell.b, 1e-9)
+
+	// the inellipse touches the triangle edges at their midpoints
+	testCases := [][3][2]float64{
+		v,
+		{{0, 0}, {4.0, 0}, {1.0, 3.0}},
+		{{-1.0, 2.0}, {5.0, -1.0}, {0.5, 0.5}},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewSteinerInellipse(tc[0][0], tc[0][1], tc[1][0], tc[1][1], tc[2][0], tc[2][1])
+		assert.NoError(err)
+		assert.InDelta((tc[0][0]+tc[1][0]+tc[2][0])/3, ell.x, 1e-9)
+		assert.InDelta((tc[0][1]+tc[1][1]+tc[2][1])/3, ell.y, 1e-9)
+
+		for i := range tc {
+			p, q := tc[i], tc[(i+1)%3]
+			mx, my := (p[0]+q[0])/2, (p[1]+q[1])/2
+			assert.InDelta(0, ell.Implicit(mx, my), 1e-9)
+
+			// the implicit gradient at the midpoint is perpendicular to the edge
+			gx, gy := ell.ImplicitGradient(mx, my)
+			ex, ey := q[0]-p[0], q[1]-p[1]
+			assert.InDelta(0, (gx*ex+gy*ey)/(math.Hypot(gx, gy)*math.Hypot(ex, ey)), 1e-9)
+		}
+	}
+
+	ell, err = NewSteinerInellipse(0, 0, 1.0, 1.0, 2.0, 2.0)
+	assert.Error(err)
+	assert.Nil(ell)
+
+	ell, err = NewSteinerInellipse(1.0, 1.0, 1.0, 1.0, 2.0, 3.0)
+	assert.Error(err)
+	assert.Nil(ell)
+}
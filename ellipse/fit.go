@@ -0,0 +1,493 @@
+package ellipse
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/plot/plotter"
+)
+
+const (
+	// minFitPoints is the minimum number of points required to fit an ellipse
+	minFitPoints = 5
+	// geometricFitTol is the parameter update norm below which the geometric fit is considered converged
+	geometricFitTol = 1e-10
+	// maxStepHalvings is the maximum number of step halvings in a single geometric fit iteration
+	maxStepHalvings = 30
+)
+
+// FitStats contains the diagnostics of the ellipse fit.
+type FitStats struct {
+	// RMSE is the root mean square of the geometric distances of the points from the fitted ellipse
+	RMSE float64
+	// MaxResidual is the largest geometric distance of a point from the fitted ellipse
+	MaxResidual float64
+	// Iterations is the number of iterations performed by iterative fits
+	Iterations int
+	// Converged is true if the fit converged
+	Converged bool
+}
+
+// NewFromFit creates new Ellipse by fitting it to the points stored in the first two columns of data.
+// The ellipse is fitted using the direct least squares method.
+// It returns error if data has less than 2 columns or less than 5 rows or if the ellipse could not be fitted.
+func NewFromFit(data mat.Matrix) (*Ellipse, error) {
+	return fit(data, nil)
+}
+
+// NewFromFitWithStats creates new Ellipse by fitting it to the points stored in the first two columns of data
+// just like NewFromFit does and returns it along with the fit diagnostics.
+// The direct least squares method is not iterative, so the returned stats report zero iterations
+// and the fit is always reported as converged.
+// It returns error if data has less than 2 columns or less than 5 rows or if the ellipse could not be fitted.
+func NewFromFitWithStats(data mat.Matrix) (*Ellipse, FitStats, error) {
+	e, err := fit(data, nil)
+	if err != nil {
+		return nil, FitStats{}, err
+	}
+
+	stats := e.fitStats(data)
+	stats.Converged = true
+
+	return e, stats, nil
+}
+
+// NewFromWeightedFit creates new Ellipse by fitting it to the points stored in the first two columns of data
+// using the direct least squares method, where the residual of each point is weighted by the corresponding weight.
+// It returns error if the number of weights does not match the number of points, if any of the weights is negative,
+// if data has less than 2 columns or less than 5 rows or if the ellipse could not be fitted.
+func NewFromWeightedFit(data mat.Matrix, weights []float64) (*Ellipse, error) {
+	if rows, _ := data.Dims(); len(weights) != rows {
+		return nil, fmt.Errorf("Invalid number of weights: %d, expected: %d", len(weights), rows)
+	}
+
+	for _, w := range weights {
+		if w < 0 {
+			return nil, fmt.Errorf("Invalid weight: %.2f", w)
+		}
+	}
+
+	return fit(data, weights)
+}
+
+// NewFromXYs creates new Ellipse by fitting it to pts using the direct least squares method.
+// It returns error if pts contains less than 5 points or if the ellipse could not be fitted.
+func NewFromXYs(pts plotter.XYs) (*Ellipse, error) {
+	if len(pts) < minFitPoints {
+		return nil, fmt.Errorf("Insufficient number of points: %d", len(pts))
+	}
+
+	data := mat.NewDense(len(pts), 2, nil)
+	for i, p := range pts {
+		data.Set(i, 0, p.X)
+		data.Set(i, 1, p.Y)
+	}
+
+	return fit(data, nil)
+}
+
+// NewFromRANSACFit creates new Ellipse by robustly fitting it to the points stored in the first two columns of data
+// using RANSAC. In each of the iterations an ellipse is fitted to randomly sampled 5 points and the points whose
+// geometric distance from the fitted ellipse is below threshold are counted as its inliers. The ellipse with the most
+// inliers is refitted to all of its inliers and returned along with the indices of the inlier data rows.
+// The points are sampled using src random source. If src is nil, a source seeded with a fixed seed is used.
+// It returns error if threshold or iterations are not positive, data has less than 2 columns or less than 5 rows
+// or if no ellipse could be fitted.
+//
+// For more information see: https://en.wikipedia.org/wiki/Random_sample_consensus
+func NewFromRANSACFit(data mat.Matrix, threshold float64, iterations int, src rand.Source) (*Ellipse, []int, error) {
+	if !(threshold > 0) {
+		return nil, nil, fmt.Errorf("Invalid threshold: %v", threshold)
+	}
+	if iterations <= 0 {
+		return nil, nil, fmt.Errorf("Invalid number of iterations: %d", iterations)
+	}
+
+	rows, cols := data.Dims()
+	if cols < 2 {
+		return nil, nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+	if rows < minFitPoints {
+		return nil, nil, fmt.Errorf("Insufficient number of points: %d", rows)
+	}
+
+	rnd := newRand(src)
+	idx := make([]int, rows)
+	for i := range idx {
+		idx[i] = i
+	}
+	sample := mat.NewDense(minFitPoints, 2, nil)
+
+	var best *Ellipse
+	var bestInliers []int
+	for iter := 0; iter < iterations; iter++ {
+		// partial Fisher-Yates shuffle selects minFitPoints distinct points
+		for i := 0; i < minFitPoints; i++ {
+			j := i + rnd.Intn(rows-i)
+			idx[i], idx[j] = idx[j], idx[i]
+			sample.Set(i, 0, data.At(idx[i], 0))
+			sample.Set(i, 1, data.At(idx[i], 1))
+		}
+
+		e, err := fit(sample, nil)
+		if err != nil {
+			continue
+		}
+
+		if inliers := e.inliers(data, threshold); len(inliers) > len(bestInliers) {
+			best, bestInliers = e, inliers
+		}
+	}
+
+	if best == nil {
+		return nil, nil, fmt.Errorf("Could not fit ellipse")
+	}
+
+	// refit the ellipse to all of its inliers
+	inData := mat.NewDense(len(bestInliers), 2, nil)
+	for i, j := range bestInliers {
+		inData.Set(i, 0, data.At(j, 0))
+		inData.Set(i, 1, data.At(j, 1))
+	}
+	if e, err := fit(inData, nil); err == nil {
+		if inliers := e.inliers(data, threshold); len(inliers) >= len(bestInliers) {
+			best, bestInliers = e, inliers
+		}
+	}
+
+	return best, bestInliers, nil
+}
+
+// NewFromGeometricFit creates new Ellipse by fitting it to the points stored in the first two columns of data
+// so that the sum of the squared geometric distances of the points from the ellipse boundary is minimal.
+// The initial ellipse is iteratively refined using Gauss-Newton method for at most maxIter iterations.
+// The initial ellipse is typically obtained by cheaper, but biased, algebraic fit such as NewFromFit.
+// It returns error if initial is nil, maxIter is not positive, data has less than 2 columns or less than 5 rows
+// or if the ellipse could not be fitted.
+//
+// For more information see: S. J. Ahn et al.: Orthogonal Distance Fitting of Implicit Curves and Surfaces
+func NewFromGeometricFit(data mat.Matrix, initial *Ellipse, maxIter int) (*Ellipse, error) {
+	e, _, err := NewFromGeometricFitWithStats(data, initial, maxIter)
+
+	return e, err
+}
+
+// NewFromGeometricFitWithStats creates new Ellipse by fitting it to the points stored in the first two columns of data
+// just like NewFromGeometricFit does and returns it along with the fit diagnostics.
+// The returned stats report the number of Gauss-Newton iterations performed and whether the fit converged.
+// The fit is not converged if maxIter iterations were exhausted or if the fit stalled
+// i.e. none of the halved Gauss-Newton steps decreased the sum of the squared distances.
+// It returns error if initial is nil, maxIter is not positive, data has less than 2 columns or less than 5 rows
+// or if the ellipse could not be fitted.
+func NewFromGeometricFitWithStats(data mat.Matrix, initial *Ellipse, maxIter int) (*Ellipse, FitStats, error) {
+	e, iter, converged, err := geometricFit(data, initial, maxIter)
+	if err != nil {
+		return nil, FitStats{}, err
+	}
+
+	stats := e.fitStats(data)
+	stats.Iterations = iter
+	stats.Converged = converged
+
+	return e, stats, nil
+}
+
+// geometricFit refines initial ellipse by minimizing the sum of the squared geometric distances
+// of the points stored in the first two columns of data from the ellipse boundary.
+// It returns the fitted ellipse, the number of iterations performed and whether the fit converged.
+func geometricFit(data mat.Matrix, initial *Ellipse, maxIter int) (*Ellipse, int, bool, error) {
+	if initial == nil {
+		return nil, 0, false, fmt.Errorf("Missing initial ellipse")
+	}
+	if maxIter <= 0 {
+		return nil, 0, false, fmt.Errorf("Invalid number of iterations: %d", maxIter)
+	}
+
+	rows, cols := data.Dims()
+	if cols < 2 {
+		return nil, 0, false, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+	if rows < minFitPoints {
+		return nil, 0, false, fmt.Errorf("Insufficient number of points: %d", rows)
+	}
+
+	e := *initial
+	// the fitted ellipse is not a confidence region of any Gaussian distribution
+	e.scale = 0
+	// minimizing the RMSE minimizes the sum of the squared distances
+	cost := e.fitStats(data).RMSE
+
+	jac := mat.NewDense(rows, 5, nil)
+	res := mat.NewVecDense(rows, nil)
+	var step mat.VecDense
+
+	for iter := 1; iter <= maxIter; iter++ {
+		for i := 0; i < rows; i++ {
+			r, row := e.distanceJacobian(data.At(i, 0), data.At(i, 1))
+			res.SetVec(i, r)
+			jac.SetRow(i, row[:])
+		}
+
+		if err := step.SolveVec(jac, res); err != nil {
+			return nil, iter, false, fmt.Errorf("Could not fit ellipse: %v", err)
+		}
+
+		// negligible Gauss-Newton step means the fit has converged
+		if mat.Norm(&step, 2) < geometricFitTol {
+			e.angle = e.NormalizedAngle()
+			return &e, iter, true, nil
+		}
+
+		// halve the step until it decreases the cost
+		improved := false
+		for h := 0; h < maxStepHalvings; h++ {
+			c := e
+			c.x += step.AtVec(0)
+			c.y += step.AtVec(1)
+			c.a += step.AtVec(2)
+			c.b += step.AtVec(3)
+			c.angle += step.AtVec(4)
+
+			if c.a > 0 && c.b > 0 {
+				if cc := c.fitStats(data).RMSE; cc <= cost {
+					e, cost, improved = c, cc, true
+					break
+				}
+			}
+			step.ScaleVec(0.5, &step)
+		}
+
+		// none of the halved steps decreased the cost, so the fit has stalled
+		if !improved {
+			e.angle = e.NormalizedAngle()
+			return &e, iter, false, nil
+		}
+
+		if mat.Norm(&step, 2) < geometricFitTol {
+			e.angle = e.NormalizedAngle()
+			return &e, iter, true, nil
+		}
+	}
+
+	e.angle = e.NormalizedAngle()
+
+	return &e, maxIter, false, nil
+}
+
+// RMSEGradient returns the gradient of the root mean square of the geometric distances of the points
+// stored in the first two columns of data from the ellipse with respect to the ellipse parameters [x, y, a, b, angle].
+// The gradient is computed analytically from the boundary Jacobian at the closest boundary points,
+// so it can be used along with the RMSE as an objective of gonum optimize package.
+// The RMSE is not differentiable when all the points lie exactly on the ellipse boundary, in which case zero gradient is returned.
+// It returns error if data has less than 2 columns or no rows.
+func (e *Ellipse) RMSEGradient(data mat.Matrix) ([]float64, error) {
+	rows, cols := data.Dims()
+	if cols < 2 {
+		return nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("Insufficient number of points: %d", rows)
+	}
+
+	grad := make([]float64, 5)
+	sum := 0.0
+	for i := 0; i < rows; i++ {
+		r, row := e.distanceJacobian(data.At(i, 0), data.At(i, 1))
+		sum += r * r
+		// moving the ellipse boundary along the normal decreases the signed distance of the point
+		for j := range grad {
+			grad[j] -= r * row[j]
+		}
+	}
+
+	rmse := math.Sqrt(sum / float64(rows))
+	if rmse == 0 {
+		return grad, nil
+	}
+
+	floats.Scale(1/(float64(rows)*rmse), grad)
+
+	return grad, nil
+}
+
+// distanceJacobian returns the signed distance of the point [x,y] from the ellipse boundary
+// along with the projection of the boundary Jacobian at its closest boundary point onto the boundary normal.
+// The projection is the derivative of the closest boundary point displacement along the normal with respect
+// to the ellipse parameters [x, y, a, b, angle] i.e. the negative derivative of the signed distance.
+func (e *Ellipse) distanceJacobian(x, y float64) (float64, [5]float64) {
+	cx, cy := e.ClosestPoint(x, y)
+	u, v := e.local(cx, cy)
+	theta := math.Atan2(v/e.b, u/e.a)
+
+	_, _, nx, ny := e.NormalAt(theta)
+
+	var row [5]float64
+	bj := e.BoundaryJacobian(theta)
+	for j := range row {
+		row[j] = nx*bj.At(0, j) + ny*bj.At(1, j)
+	}
+
+	return nx*(x-cx) + ny*(y-cy), row
+}
+
+// fitStats returns the residual statistics of the points stored in the first two columns of data.
+func (e *Ellipse) fitStats(data mat.Matrix) FitStats {
+	rows, _ := data.Dims()
+
+	var stats FitStats
+	sum := 0.0
+	for i := 0; i < rows; i++ {
+		d := e.distance(data.At(i, 0), data.At(i, 1))
+		sum += d * d
+		stats.MaxResidual = math.Max(stats.MaxResidual, d)
+	}
+	stats.RMSE = math.Sqrt(sum / float64(rows))
+
+	return stats
+}
+
+// inliers returns the indices of data rows whose geometric distance from the ellipse is below threshold.
+func (e *Ellipse) inliers(data mat.Matrix, threshold float64) []int {
+	rows, _ := data.Dims()
+
+	var inliers []int
+	for i := 0; i < rows; i++ {
+		if e.distance(data.At(i, 0), data.At(i, 1)) < threshold {
+			inliers = append(inliers, i)
+		}
+	}
+
+	return inliers
+}
+
+// fit fits an ellipse to the points stored in the first two columns of data
+// using Halir and Flusser formulation of Fitzgibbon's direct least squares method.
+// If weights is not nil, the squared algebraic residual of each point is weighted by the corresponding weight.
+// The data are normalized prior to fitting for numerical stability.
+//
+// For more information see: R. Halir, J. Flusser: Numerically Stable Direct Least Squares Fitting of Ellipses
+func fit(data mat.Matrix, weights []float64) (*Ellipse, error) {
+	rows, cols := data.Dims()
+	if cols < 2 {
+		return nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+	if rows < minFitPoints {
+		return nil, fmt.Errorf("Insufficient number of points: %d", rows)
+	}
+
+	// normalize the data to zero mean and unit standard deviation
+	xs := mat.Col(nil, 0, data)
+	ys := mat.Col(nil, 1, data)
+	mx, sx := stat.MeanStdDev(xs, nil)
+	my, sy := stat.MeanStdDev(ys, nil)
+	s := math.Sqrt((sx*sx + sy*sy) / 2)
+	if !(s > 0) {
+		return nil, fmt.Errorf("Could not fit ellipse to identical points")
+	}
+
+	// quadratic and linear parts of the design matrix
+	d1 := mat.NewDense(rows, 3, nil)
+	d2 := mat.NewDense(rows, 3, nil)
+	for i := 0; i < rows; i++ {
+		x, y := (xs[i]-mx)/s, (ys[i]-my)/s
+		w := 1.0
+		if weights != nil {
+			w = math.Sqrt(weights[i])
+		}
+		d1.SetRow(i, []float64{w * x * x, w * x * y, w * y * y})
+		d2.SetRow(i, []float64{w * x, w * y, w})
+	}
+
+	// scatter matrices
+	var s1, s2, s3 mat.Dense
+	s1.Mul(d1.T(), d1)
+	s2.Mul(d1.T(), d2)
+	s3.Mul(d2.T(), d2)
+
+	// t = -s3^-1 * s2^T
+	var t mat.Dense
+	if err := t.Solve(&s3, s2.T()); err != nil {
+		return nil, fmt.Errorf("Could not fit ellipse: %v", err)
+	}
+	t.Scale(-1, &t)
+
+	// reduced scatter matrix m = s1 + s2 * t premultiplied by the inverse of the constraint matrix
+	var m mat.Dense
+	m.Mul(&s2, &t)
+	m.Add(&s1, &m)
+	c := mat.NewDense(3, 3, nil)
+	c.SetRow(0, []float64{m.At(2, 0) / 2, m.At(2, 1) / 2, m.At(2, 2) / 2})
+	c.SetRow(1, []float64{-m.At(1, 0), -m.At(1, 1), -m.At(1, 2)})
+	c.SetRow(2, []float64{m.At(0, 0) / 2, m.At(0, 1) / 2, m.At(0, 2) / 2})
+
+	var eig mat.Eigen
+	if ok := eig.Factorize(c, mat.EigenRight); !ok {
+		return nil, fmt.Errorf("Could not fit ellipse: eigen decomposition failed")
+	}
+	vals := eig.Values(nil)
+	var vecs mat.CDense
+	eig.VectorsTo(&vecs)
+
+	// the ellipse solution is the eigenvector which satisfies 4ac - b^2 > 0
+	best := -1
+	for j := range vals {
+		a, b, cc := vecs.At(0, j), vecs.At(1, j), vecs.At(2, j)
+		if math.Abs(imag(a))+math.Abs(imag(b))+math.Abs(imag(cc)) > 1e-9 {
+			continue
+		}
+		if 4*real(a)*real(cc)-real(b)*real(b) <= 0 {
+			continue
+		}
+		if best < 0 || cmplx.Abs(vals[j]) < cmplx.Abs(vals[best]) {
+			best = j
+		}
+	}
+	if best < 0 {
+		return nil, fmt.Errorf("Could not fit ellipse: no elliptic solution found")
+	}
+
+	a1 := mat.NewVecDense(3, []float64{real(vecs.At(0, best)), real(vecs.At(1, best)), real(vecs.At(2, best))})
+	var a2 mat.VecDense
+	a2.MulVec(&t, a1)
+
+	e, err := fromConic(a1.AtVec(0), a1.AtVec(1), a1.AtVec(2), a2.AtVec(0), a2.AtVec(1), a2.AtVec(2))
+	if err != nil {
+		return nil, err
+	}
+
+	// denormalize the fitted ellipse
+	e.x, e.y = e.x*s+mx, e.y*s+my
+	e.a, e.b = e.a*s, e.b*s
+
+	return e, nil
+}
+
+// fromConic creates new Ellipse from the coefficients of the general conic equation
+// A*x^2 + B*x*y + C*y^2 + D*x + E*y + F = 0.
+// It returns error if the coefficients do not represent a real ellipse.
+//
+// For more information see: https://en.wikipedia.org/wiki/Ellipse#General_ellipse
+func fromConic(a, b, c, d, e, f float64) (*Ellipse, error) {
+	det := 4*a*c - b*b
+	if !(det > 0) {
+		return nil, fmt.Errorf("Conic is not an ellipse")
+	}
+
+	// ellipse center is the point where the conic gradient vanishes
+	x := (b*e - 2*c*d) / det
+	y := (b*d - 2*a*e) / det
+
+	// the value of the conic equation at the center
+	f0 := a*x*x + b*x*y + c*y*y + d*x + e*y + f
+
+	// shape matrix is the inverse of the quadratic form normalized by -f0
+	k := -4 * f0 / det
+	shape := mat.NewSymDense(2, []float64{k * c, -k * b / 2, -k * b / 2, k * a})
+
+	return newFromShape(x, y, shape)
+}
@@ -0,0 +1,385 @@
+package ellipse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot/plotter"
+)
+
+// boundaryData returns size number of points sampled uniformly from the ellipse parametric angle
+func boundaryData(e *Ellipse, size int) *mat.Dense {
+	data := mat.NewDense(size, 2, nil)
+	for i := 0; i < size; i++ {
+		x, y := e.PointAt(2 * math.Pi * float64(i) / float64(size))
+		data.Set(i, 0, x)
+		data.Set(i, 1, y)
+	}
+
+	return data
+}
+
+func TestNewFromFit(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []*Ellipse{
+		{x: 1.0, y: 2.0, a: 4.0, b: 1.0, angle: math.Pi / 6},
+		{x: -10.0, y: 5.0, a: 30.0, b: 20.0, angle: 2.5},
+		{x: 100.0, y: 200.0, a: 3.0, b: 2.0},
+	}
+
+	for _, exp := range testCases {
+		ell, err := NewFromFit(boundaryData(exp, 20))
+		assert.NoError(err)
+		assert.InDelta(exp.x, ell.x, 1e-6)
+		assert.InDelta(exp.y, ell.y, 1e-6)
+		assert.InDelta(exp.a, ell.a, 1e-6)
+		assert.InDelta(exp.b, ell.b, 1e-6)
+		assert.InDelta(exp.NormalizedAngle(), ell.NormalizedAngle(), 1e-6)
+	}
+
+	testData := []*mat.Dense{
+		mat.NewDense(5, 1, nil),
+		mat.NewDense(4, 2, []float64{1.0, 0, 0, 1.0, -1.0, 0, 0, -1.0}),
+		mat.NewDense(5, 2, []float64{1.0, 1.0, 1.0, 1.0, 1.0, 1.0, 1.0, 1.0, 1.0, 1.0}),
+	}
+
+	for _, data := range testData {
+		ell, err := NewFromFit(data)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestNewFromFitWithStats(t *testing.T) {
+	assert := assert.New(t)
+
+	exp := &Ellipse{x: 1.0, y: 2.0, a: 4.0, b: 1.0, angle: math.Pi / 6}
+	data := boundaryData(exp, 20)
+
+	ell, stats, err := NewFromFitWithStats(data)
+	assert.NoError(err)
+	assert.True(exp.ApproxEqual(ell, 1e-6))
+	assert.InDelta(0, stats.RMSE, 1e-6)
+	assert.InDelta(0, stats.MaxResidual, 1e-6)
+	assert.Equal(0, stats.Iterations)
+	assert.True(stats.Converged)
+
+	// a point off the ellipse increases the residuals
+	data.Set(0, 0, data.At(0, 0)+0.5)
+	ell, stats, err = NewFromFitWithStats(data)
+	assert.NoError(err)
+	assert.True(stats.RMSE > 1e-3)
+	assert.True(stats.MaxResidual >= stats.RMSE)
+	assert.InDelta(ell.distance(data.At(0, 0), data.At(0, 1)), stats.MaxResidual, 1e-12)
+
+	ell, stats, err = NewFromFitWithStats(mat.NewDense(4, 2, nil))
+	assert.Error(err)
+	assert.Nil(ell)
+	assert.Equal(FitStats{}, stats)
+}
+
+func TestNewFromXYs(t *testing.T) {
+	assert := assert.New(t)
+
+	exp := &Ellipse{x: 1.0, y: 2.0, a: 4.0, b: 1.0, angle: math.Pi / 6}
+
+	pts := make(plotter.XYs, 10)
+	for i := range pts {
+		pts[i].X, pts[i].Y = exp.PointAt(2 * math.Pi * float64(i) / float64(len(pts)))
+	}
+
+	ell, err := NewFromXYs(pts)
+	assert.NoError(err)
+	assert.InDelta(exp.x, ell.x, 1e-6)
+	assert.InDelta(exp.y, ell.y, 1e-6)
+	assert.InDelta(exp.a, ell.a, 1e-6)
+	assert.InDelta(exp.b, ell.b, 1e-6)
+	assert.InDelta(exp.angle, ell.angle, 1e-6)
+
+	for _, pts := range []plotter.XYs{nil, pts[:4]} {
+		ell, err = NewFromXYs(pts)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestNewFromWeightedFit(t *testing.T) {
+	assert := assert.New(t)
+
+	exp := &Ellipse{x: 1.0, y: 2.0, a: 4.0, b: 1.0, angle: math.Pi / 6}
+	size := 20
+
+	// add an off curve point to the boundary points
+	data := mat.NewDense(size+1, 2, nil)
+	data.Slice(0, size, 0, 2).(*mat.Dense).Copy(boundaryData(exp, size))
+	data.Set(size, 0, 6.0)
+	data.Set(size, 1, 0)
+
+	weights := make([]float64, size+1)
+	for i := range weights {
+		weights[i] = 1.0
+	}
+
+	unweighted, err := NewFromWeightedFit(data, weights)
+	assert.NoError(err)
+
+	fitted, err := NewFromFit(data)
+	assert.NoError(err)
+	assert.InDelta(fitted.a, unweighted.a, 1e-9)
+	assert.InDelta(fitted.b, unweighted.b, 1e-9)
+
+	weights[size] = 0.01
+	weighted, err := NewFromWeightedFit(data, weights)
+	assert.NoError(err)
+
+	paramErr := func(e *Ellipse) float64 {
+		return math.Abs(e.x-exp.x) + math.Abs(e.y-exp.y) + math.Abs(e.a-exp.a) + math.Abs(e.b-exp.b)
+	}
+	assert.True(paramErr(weighted) < paramErr(unweighted))
+
+	testCases := []struct {
+		m *mat.Dense
+		w []float64
+	}{
+		{data, weights[:size]},
+		{data, nil},
+		{data, append([]float64{-1.0}, weights[1:]...)},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewFromWeightedFit(tc.m, tc.w)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestNewFromRANSACFit(t *testing.T) {
+	assert := assert.New(t)
+
+	exp := &Ellipse{x: 1.0, y: 2.0, a: 4.0, b: 2.0, angle: math.Pi / 6}
+	inliers, outliers := 40, 20
+
+	rnd := rand.New(rand.NewSource(1))
+	data := mat.NewDense(inliers+outliers, 2, nil)
+	for i := 0; i < inliers; i++ {
+		x, y := exp.PointAt(2 * math.Pi * rnd.Float64())
+		data.Set(i, 0, x+0.01*rnd.NormFloat64())
+		data.Set(i, 1, y+0.01*rnd.NormFloat64())
+	}
+	for i := inliers; i < inliers+outliers; i++ {
+		data.Set(i, 0, exp.x+10*(rnd.Float64()-0.5))
+		data.Set(i, 1, exp.y+10*(rnd.Float64()-0.5))
+	}
+
+	ell, idx, err := NewFromRANSACFit(data, 0.05, 200, rand.NewSource(2))
+	assert.NoError(err)
+	assert.InDelta(exp.x, ell.x, 0.05)
+	assert.InDelta(exp.y, ell.y, 0.05)
+	assert.InDelta(exp.a, ell.a, 0.05)
+	assert.InDelta(exp.b, ell.b, 0.05)
+	assert.InDelta(exp.angle, ell.angle, 0.05)
+
+	// all the inliers must be found and only a few outliers may happen to lie close to the ellipse
+	found := 0
+	for _, i := range idx {
+		if i < inliers {
+			found++
+		}
+	}
+	assert.Equal(inliers, found)
+	assert.True(len(idx)-found <= 3)
+
+	// plain least squares fit is thrown off by the outliers
+	lsq, err := NewFromFit(data)
+	assert.NoError(err)
+	assert.True(math.Abs(lsq.a-exp.a)+math.Abs(lsq.b-exp.b) > math.Abs(ell.a-exp.a)+math.Abs(ell.b-exp.b))
+
+	testCases := []struct {
+		m         *mat.Dense
+		threshold float64
+		iter      int
+	}{
+		{data, 0, 100},
+		{data, 0.05, 0},
+		{mat.NewDense(5, 1, nil), 0.05, 100},
+		{mat.NewDense(4, 2, nil), 0.05, 100},
+	}
+
+	for _, tc := range testCases {
+		ell, idx, err := NewFromRANSACFit(tc.m, tc.threshold, tc.iter, nil)
+		assert.Error(err)
+		assert.Nil(ell)
+		assert.Nil(idx)
+	}
+}
+
+func TestNewFromGeometricFit(t *testing.T) {
+	assert := assert.New(t)
+
+	exp := &Ellipse{x: 1.0, y: 2.0, a: 4.0, b: 2.0, angle: math.Pi / 6}
+	size := 50
+
+	// noisy points sampled from the half of the ellipse bias the algebraic fit
+	rnd := rand.New(rand.NewSource(1))
+	data := mat.NewDense(size, 2, nil)
+	for i := 0; i < size; i++ {
+		x, y := exp.PointAt(math.Pi * rnd.Float64())
+		data.Set(i, 0, x+0.1*rnd.NormFloat64())
+		data.Set(i, 1, y+0.1*rnd.NormFloat64())
+	}
+
+	alg, err := NewFromFit(data)
+	assert.NoError(err)
+
+	ell, err := NewFromGeometricFit(data, alg, 100)
+	assert.NoError(err)
+
+	assert.True(ell.fitStats(data).RMSE < alg.fitStats(data).RMSE)
+	assert.InDelta(exp.x, ell.x, 0.2)
+	assert.InDelta(exp.y, ell.y, 0.2)
+	assert.InDelta(exp.a, ell.a, 0.2)
+	assert.InDelta(exp.b, ell.b, 0.2)
+
+	// the exact ellipse is a fixed point of the fit
+	data = boundaryData(exp, size)
+	ell, err = NewFromGeometricFit(data, exp, 10)
+	assert.NoError(err)
+	assert.True(exp.ApproxEqual(ell, 1e-9))
+
+	// the fixed point is reported as converged in the first iteration
+	ell, stats, err := NewFromGeometricFitWithStats(data, exp, 10)
+	assert.NoError(err)
+	assert.True(stats.Converged)
+	assert.Equal(1, stats.Iterations)
+	assert.InDelta(0, stats.RMSE, 1e-9)
+	assert.True(exp.ApproxEqual(ell, 1e-9))
+
+	testCases := []struct {
+		m       *mat.Dense
+		initial *Ellipse
+		iter    int
+	}{
+		{data, nil, 10},
+		{data, exp, 0},
+		{mat.NewDense(5, 1, nil), exp, 10},
+		{mat.NewDense(4, 2, nil), exp, 10},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewFromGeometricFit(tc.m, tc.initial, tc.iter)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestGeometricFitCovarianceMetadata(t *testing.T) {
+	assert := assert.New(t)
+
+	data := gaussData(100, 1.0, 2.0, 1)
+	initial, err := NewWithDataConfidence(data, 0.95)
+	assert.NoError(err)
+
+	// fit the confidence ellipse to its own boundary
+	ell, err := NewFromGeometricFit(boundaryData(initial, 50), initial, 10)
+	assert.NoError(err)
+
+	_, err = ell.Mahalanobis(1.0, 2.0)
+	assert.Error(err)
+
+	_, err = ell.PValue(1.0, 2.0)
+	assert.Error(err)
+
+	// the initial ellipse is not modified
+	_, err = initial.Mahalanobis(1.0, 2.0)
+	assert.NoError(err)
+}
+
+func TestNewFromGeometricFitWithStats(t *testing.T) {
+	assert := assert.New(t)
+
+	exp := &Ellipse{x: 1.0, y: 2.0, a: 4.0, b: 2.0, angle: math.Pi / 6}
+	size := 50
+
+	rnd := rand.New(rand.NewSource(1))
+	data := mat.NewDense(size, 2, nil)
+	for i := 0; i < size; i++ {
+		x, y := exp.PointAt(2 * math.Pi * rnd.Float64())
+		data.Set(i, 0, x+0.05*rnd.NormFloat64())
+		data.Set(i, 1, y+0.05*rnd.NormFloat64())
+	}
+
+	alg, err := NewFromFit(data)
+	assert.NoError(err)
+
+	// a single iteration is not enough to converge
+	ell, stats, err := NewFromGeometricFitWithStats(data, alg, 1)
+	assert.NoError(err)
+	assert.Equal(1, stats.Iterations)
+	assert.False(stats.Converged)
+	assert.InDelta(ell.fitStats(data).RMSE, stats.RMSE, 1e-12)
+
+	ell, stats, err = NewFromGeometricFitWithStats(data, alg, 100)
+	assert.NoError(err)
+	assert.True(stats.Iterations > 1)
+	assert.True(stats.Iterations < 100)
+	assert.True(stats.Converged)
+	assert.True(stats.RMSE > 0)
+	assert.True(stats.MaxResidual >= stats.RMSE)
+
+	plain, err := NewFromGeometricFit(data, alg, 100)
+	assert.NoError(err)
+	assert.Equal(plain, ell)
+
+	ell, stats, err = NewFromGeometricFitWithStats(data, nil, 10)
+	assert.Error(err)
+	assert.Nil(ell)
+	assert.Equal(FitStats{}, stats)
+}
+
+func TestRMSEGradient(t *testing.T) {
+	assert := assert.New(t)
+
+	data := boundaryData(&Ellipse{x: 1.0, y: 2.0, a: 4.0, b: 2.0, angle: math.Pi / 6}, 12)
+
+	ell := &Ellipse{x: 1.3, y: 1.8, a: 3.5, b: 2.4, angle: 0.7}
+	grad, err := ell.RMSEGradient(data)
+	assert.NoError(err)
+	assert.Len(grad, 5)
+
+	rmse := func(p []float64) float64 {
+		e, err := NewFromParams(p)
+		assert.NoError(err)
+		return e.fitStats(data).RMSE
+	}
+
+	// compare against central finite differences
+	h := 1e-6
+	p := ell.Params()
+	for j := range p {
+		fwd := append([]float64(nil), p...)
+		bwd := append([]float64(nil), p...)
+		fwd[j] += h
+		bwd[j] -= h
+		assert.InDelta((rmse(fwd)-rmse(bwd))/(2*h), grad[j], 1e-6)
+	}
+
+	// a small step against the gradient decreases the RMSE
+	next := append([]float64(nil), p...)
+	for j := range next {
+		next[j] -= 1e-3 * grad[j]
+	}
+	assert.True(rmse(next) < rmse(p))
+
+	grad, err = ell.RMSEGradient(mat.NewDense(3, 1, []float64{1.0, 2.0, 3.0}))
+	assert.Error(err)
+	assert.Nil(grad)
+
+	// empty data has no RMSE
+	grad, err = ell.RMSEGradient(mat.NewDense(3, 2, nil).Slice(0, 0, 0, 2))
+	assert.Error(err)
+	assert.Nil(grad)
+}
@@ -0,0 +1,327 @@
+package ellipse
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// checkConfidence returns error if confidence is not in (0,1) interval.
+// Confidence equal to 1 is rejected explicitly: the region which contains the whole
+// probability mass of Gaussian distribution is unbounded.
+func checkConfidence(confidence float64) error {
+	if confidence == 1 {
+		return fmt.Errorf("Invalid confidence level: confidence must be < 1; 100%% region is unbounded")
+	}
+
+	if !(confidence > 0 && confidence < 1) {
+		return fmt.Errorf("Invalid confidence level: %.2f", confidence)
+	}
+
+	return nil
+}
+
+// ConfidenceToRadius returns the Mahalanobis distance from the mean of 2D Gaussian distribution
+// which encloses confidence probability mass of the distribution.
+// It returns error if confidence is not in (0,1) interval.
+func ConfidenceToRadius(confidence float64) (float64, error) {
+	if err := checkConfidence(confidence); err != nil {
+		return 0, err
+	}
+
+	chi2 := distuv.ChiSquared{K: 2}
+
+	return math.Sqrt(chi2.Quantile(confidence)), nil
+}
+
+// RadiusToConfidence returns the probability mass of 2D Gaussian distribution
+// enclosed within radius Mahalanobis distance from the distribution mean.
+// It is the inverse of ConfidenceToRadius.
+func RadiusToConfidence(radius float64) float64 {
+	return CoverageProbability(radius)
+}
+
+// CoverageProbability returns the probability mass of 2D Gaussian distribution
+// enclosed by the ellipse whose boundary is mahalanobis distance away from the distribution mean.
+// It is the inverse of the mapping of confidence to ellipse axes scale used by NewWithDataConfidence.
+func CoverageProbability(mahalanobis float64) float64 {
+	chi2 := distuv.ChiSquared{K: 2}
+
+	return chi2.CDF(mahalanobis * mahalanobis)
+}
+
+// NewFromCovariance creates new confidence Ellipse with origin [x,y] which contains confidence probability mass
+// of 2D Gaussian distribution with mean [x,y] and covariance cov.
+// It returns error if cov is not 2x2 positive definite matrix or if confidence is not in (0,1) interval.
+func NewFromCovariance(x, y float64, cov mat.Symmetric, confidence float64) (*Ellipse, error) {
+	if n := cov.Symmetric(); n != 2 {
+		return nil, fmt.Errorf("Invalid covariance dimensions: %d", n)
+	}
+
+	radius, err := ConfidenceToRadius(confidence)
+	if err != nil {
+		return nil, err
+	}
+	scale := radius * radius
+
+	shape := mat.NewSymDense(2, nil)
+	shape.ScaleSym(scale, cov)
+
+	e, err := newFromShape(x, y, shape)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid covariance, not positive definite: %v", err)
+	}
+	e.scale = scale
+
+	return e, nil
+}
+
+// ImpliedCovariance returns the covariance of 2D Gaussian distribution whose confidence probability mass
+// is contained within the ellipse. This works for any ellipse, regardless of how it was created.
+// It returns error if confidence is not in (0,1) interval.
+func (e *Ellipse) ImpliedCovariance(confidence float64) (*mat.SymDense, error) {
+	radius, err := ConfidenceToRadius(confidence)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.scaledCovariance(radius * radius), nil
+}
+
+// Mahalanobis returns the Mahalanobis distance of the point [x,y] from the mean of the Gaussian distribution
+// the ellipse was created from. The ellipse boundary points are all the same Mahalanobis distance from the mean.
+// It returns error if the ellipse does not carry the Gaussian distribution metadata.
+//
+// For more information see: https://en.wikipedia.org/wiki/Mahalanobis_distance
+func (e *Ellipse) Mahalanobis(x, y float64) (float64, error) {
+	if e.scale <= 0 {
+		return 0, fmt.Errorf("Missing ellipse covariance metadata")
+	}
+
+	u, v := e.local(x, y)
+	u, v = u/e.a, v/e.b
+
+	return math.Sqrt(e.scale * (u*u + v*v)), nil
+}
+
+// PValue returns the probability that a point drawn from the Gaussian distribution the ellipse was created from
+// is further away from the distribution mean than the point [x,y] in terms of Mahalanobis distance
+// i.e. the complement of the Chi-squared distribution with 2 degrees of freedom CDF of the squared Mahalanobis distance.
+// It returns error if the ellipse does not carry the Gaussian distribution metadata.
+func (e *Ellipse) PValue(x, y float64) (float64, error) {
+	d, err := e.Mahalanobis(x, y)
+	if err != nil {
+		return 0, err
+	}
+
+	chi2 := distuv.ChiSquared{K: 2}
+
+	return chi2.Survival(d * d), nil
+}
+
+// MahalanobisBatch returns the Mahalanobis distances of the points stored in the first two columns of data
+// from the mean of the Gaussian distribution the ellipse was created from.
+// The precision matrix of the distribution is computed only once and shared by all the points,
+// which makes it considerably faster than calling Mahalanobis for every point.
+// It returns error if the ellipse does not carry the Gaussian distribution metadata or if data has less than 2 columns.
+func (e *Ellipse) MahalanobisBatch(data mat.Matrix) ([]float64, error) {
+	if e.scale <= 0 {
+		return nil, fmt.Errorf("Missing ellipse covariance metadata")
+	}
+
+	rows, cols := data.Dims()
+	if cols < 2 {
+		return nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+
+	// precision matrix is the inverse of the covariance matrix
+	sin, cos := math.Sincos(e.angle)
+	pa, pb := e.scale/(e.a*e.a), e.scale/(e.b*e.b)
+	p00 := cos*cos*pa + sin*sin*pb
+	p01 := sin * cos * (pa - pb)
+	p11 := sin*sin*pa + cos*cos*pb
+
+	// squared distances are the quadratic forms d^T * prec * d of the centered points d
+	dists := make([]float64, rows)
+	for i := range dists {
+		dx, dy := data.At(i, 0)-e.x, data.At(i, 1)-e.y
+		dists[i] = math.Sqrt(p00*dx*dx + 2*p01*dx*dy + p11*dy*dy)
+	}
+
+	return dists, nil
+}
+
+// covariance returns the covariance matrix of the Gaussian distribution the ellipse was created from.
+// It returns error if the ellipse does not carry the Gaussian distribution metadata.
+func (e *Ellipse) covariance() (*mat.SymDense, error) {
+	if e.scale <= 0 {
+		return nil, fmt.Errorf("Missing ellipse covariance metadata")
+	}
+
+	return e.scaledCovariance(e.scale), nil
+}
+
+// scaledCovariance returns the covariance matrix of the Gaussian distribution whose
+// squared Mahalanobis distance of the ellipse boundary from the mean is scale.
+func (e *Ellipse) scaledCovariance(scale float64) *mat.SymDense {
+	// variances along the ellipse axes
+	va := e.a * e.a / scale
+	vb := e.b * e.b / scale
+
+	// rotate the axes variances by the ellipse angle
+	sin, cos := math.Sincos(e.angle)
+
+	return mat.NewSymDense(2, []float64{
+		cos*cos*va + sin*sin*vb, sin * cos * (va - vb),
+		sin * cos * (va - vb), sin*sin*va + cos*cos*vb,
+	})
+}
+
+// Bhattacharyya returns the Bhattacharyya distance between the Gaussian distributions
+// which e and other were created from. The Bhattacharyya coefficient can be obtained as exp(-distance).
+// It returns error if either of the ellipses does not carry the Gaussian distribution metadata.
+//
+// For more information see: https://en.wikipedia.org/wiki/Bhattacharyya_distance
+func (e *Ellipse) Bhattacharyya(other *Ellipse) (float64, error) {
+	cov1, err := e.covariance()
+	if err != nil {
+		return 0, err
+	}
+
+	cov2, err := other.covariance()
+	if err != nil {
+		return 0, err
+	}
+
+	// cov = (cov1 + cov2)/2
+	cov := mat.NewSymDense(2, nil)
+	cov.AddSym(cov1, cov2)
+	cov.ScaleSym(0.5, cov)
+
+	var chol, chol1, chol2 mat.Cholesky
+	if ok := chol.Factorize(cov); !ok {
+		return 0, fmt.Errorf("Invalid ellipse covariance")
+	}
+	if ok := chol1.Factorize(cov1); !ok {
+		return 0, fmt.Errorf("Invalid ellipse covariance")
+	}
+	if ok := chol2.Factorize(cov2); !ok {
+		return 0, fmt.Errorf("Invalid ellipse covariance")
+	}
+
+	diff := mat.NewVecDense(2, []float64{e.x - other.x, e.y - other.y})
+	var sol mat.VecDense
+	if err := chol.SolveVecTo(&sol, diff); err != nil {
+		return 0, err
+	}
+
+	return mat.Dot(diff, &sol)/8 + (chol.LogDet()-(chol1.LogDet()+chol2.LogDet())/2)/2, nil
+}
+
+// KLDivergence returns the Kullback-Leibler divergence of the Gaussian distribution which other was created from
+// from the Gaussian distribution which e was created from i.e. KL(e || other).
+// It returns error if either of the ellipses does not carry the Gaussian distribution metadata.
+//
+// For more information see: https://en.wikipedia.org/wiki/Kullback%E2%80%93Leibler_divergence#Multivariate_normal_distributions
+func (e *Ellipse) KLDivergence(other *Ellipse) (float64, error) {
+	cov1, err := e.covariance()
+	if err != nil {
+		return 0, err
+	}
+
+	cov2, err := other.covariance()
+	if err != nil {
+		return 0, err
+	}
+
+	var chol1, chol2 mat.Cholesky
+	if ok := chol1.Factorize(cov1); !ok {
+		return 0, fmt.Errorf("Invalid ellipse covariance")
+	}
+	if ok := chol2.Factorize(cov2); !ok {
+		return 0, fmt.Errorf("Invalid ellipse covariance")
+	}
+
+	var m mat.Dense
+	if err := chol2.SolveTo(&m, cov1); err != nil {
+		return 0, err
+	}
+
+	diff := mat.NewVecDense(2, []float64{other.x - e.x, other.y - e.y})
+	var sol mat.VecDense
+	if err := chol2.SolveVecTo(&sol, diff); err != nil {
+		return 0, err
+	}
+
+	return (mat.Trace(&m) + mat.Dot(diff, &sol) - 2 + chol2.LogDet() - chol1.LogDet()) / 2, nil
+}
+
+// Fuse fuses the Gaussian distributions which e and other were created from and returns
+// the confidence Ellipse of the fused distribution with confidence probability.
+// The fused distribution is the normalized product of the two Gaussian distributions,
+// whose precision i.e. inverse covariance is the sum of the distributions precisions
+// and whose mean is the precision weighted average of the distributions means.
+// It returns error if either of the ellipses does not carry the Gaussian distribution metadata
+// or if confidence is not in (0,1) interval.
+//
+// For more information see: https://en.wikipedia.org/wiki/Kalman_filter#Update
+func (e *Ellipse) Fuse(other *Ellipse, confidence float64) (*Ellipse, error) {
+	if _, err := ConfidenceToRadius(confidence); err != nil {
+		return nil, err
+	}
+
+	cov1, err := e.covariance()
+	if err != nil {
+		return nil, err
+	}
+
+	cov2, err := other.covariance()
+	if err != nil {
+		return nil, err
+	}
+
+	var chol1, chol2 mat.Cholesky
+	if ok := chol1.Factorize(cov1); !ok {
+		return nil, fmt.Errorf("Invalid ellipse covariance")
+	}
+	if ok := chol2.Factorize(cov2); !ok {
+		return nil, fmt.Errorf("Invalid ellipse covariance")
+	}
+
+	var prec1, prec2 mat.SymDense
+	if err := chol1.InverseTo(&prec1); err != nil {
+		return nil, err
+	}
+	if err := chol2.InverseTo(&prec2); err != nil {
+		return nil, err
+	}
+
+	// prec = prec1 + prec2
+	prec := mat.NewSymDense(2, nil)
+	prec.AddSym(&prec1, &prec2)
+
+	var chol mat.Cholesky
+	if ok := chol.Factorize(prec); !ok {
+		return nil, fmt.Errorf("Invalid fused covariance")
+	}
+
+	cov := mat.NewSymDense(2, nil)
+	if err := chol.InverseTo(cov); err != nil {
+		return nil, err
+	}
+
+	// mean = cov * (prec1*mean1 + prec2*mean2)
+	var w1, w2 mat.VecDense
+	w1.MulVec(&prec1, mat.NewVecDense(2, []float64{e.x, e.y}))
+	w2.MulVec(&prec2, mat.NewVecDense(2, []float64{other.x, other.y}))
+	w1.AddVec(&w1, &w2)
+
+	var mean mat.VecDense
+	if err := chol.SolveVecTo(&mean, &w1); err != nil {
+		return nil, err
+	}
+
+	return NewFromCovariance(mean.AtVec(0), mean.AtVec(1), cov, confidence)
+}
@@ -0,0 +1,381 @@
+package ellipse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// gaussData generates size rows of correlated 2D Gaussian data centered at [x,y]
+func gaussData(size int, x, y float64, seed uint64) *mat.Dense {
+	rnd := rand.New(rand.NewSource(seed))
+	data := mat.NewDense(size, 2, nil)
+	for i := 0; i < size; i++ {
+		u, v := 3*rnd.NormFloat64(), rnd.NormFloat64()
+		data.Set(i, 0, x+u+v)
+		data.Set(i, 1, y+u-v)
+	}
+
+	return data
+}
+
+func TestCoverageProbability(t *testing.T) {
+	assert := assert.New(t)
+
+	chi2 := distuv.ChiSquared{K: 2}
+
+	for _, p := range []float64{0.05, 0.5, 0.68, 0.95, 0.99} {
+		assert.InDelta(p, CoverageProbability(math.Sqrt(chi2.Quantile(p))), 1e-9)
+	}
+
+	assert.Zero(CoverageProbability(0))
+	// 2D Gaussian: P(r <= m) = 1 - exp(-m^2/2)
+	assert.InDelta(1-math.Exp(-0.5), CoverageProbability(1), 1e-9)
+}
+
+func TestConfidenceRadius(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		c   float64
+		err bool
+	}{
+		{0.05, false},
+		{0.5, false},
+		{0.95, false},
+		{0.99, false},
+		{0, true},
+		{-0.5, true},
+		{1.5, true},
+		{1.0, true},
+	}
+
+	for _, tc := range testCases {
+		r, err := ConfidenceToRadius(tc.c)
+		if tc.err {
+			assert.Error(err)
+			continue
+		}
+		assert.NoError(err)
+		assert.True(r > 0)
+		assert.InDelta(tc.c, RadiusToConfidence(r), 1e-9)
+	}
+}
+
+func TestUnboundedConfidence(t *testing.T) {
+	assert := assert.New(t)
+
+	msg := "confidence must be < 1; 100% region is unbounded"
+	data := gaussData(100, 1.0, 2.0, 1)
+
+	_, err := ConfidenceToRadius(1.0)
+	assert.EqualError(err, "Invalid confidence level: "+msg)
+
+	ell, err := NewWithDataConfidence(data, 1.0)
+	assert.Nil(ell)
+	assert.Error(err)
+	assert.Contains(err.Error(), msg)
+
+	ell, err = NewWithDataConfidenceT(data, 1.0)
+	assert.Nil(ell)
+	assert.Error(err)
+	assert.Contains(err.Error(), msg)
+
+	ell, err = NewWithDataConfidenceFunc(data, 1.0, nil)
+	assert.Nil(ell)
+	assert.Error(err)
+	assert.Contains(err.Error(), msg)
+
+	ell, err = NewFromCovariance(0, 0, mat.NewSymDense(2, []float64{1.0, 0, 0, 1.0}), 1.0)
+	assert.Nil(ell)
+	assert.Error(err)
+	assert.Contains(err.Error(), msg)
+}
+
+func TestNewFromCovariance(t *testing.T) {
+	assert := assert.New(t)
+
+	cov := mat.NewSymDense(2, []float64{4.0, 0, 0, 1.0})
+	radius, err := ConfidenceToRadius(0.95)
+	assert.NoError(err)
+
+	ell, err := NewFromCovariance(1.0, 2.0, cov, 0.95)
+	assert.NoError(err)
+	assert.Equal(1.0, ell.x)
+	assert.Equal(2.0, ell.y)
+	assert.InDelta(2*radius, ell.a, 1e-9)
+	assert.InDelta(radius, ell.b, 1e-9)
+	assert.InDelta(0, ell.NormalizedAngle(), 1e-9)
+
+	// correlated covariance with principal direction along [1,1]
+	cov = mat.NewSymDense(2, []float64{10.0, 8.0, 8.0, 10.0})
+	ell, err = NewFromCovariance(0, 0, cov, 0.95)
+	assert.NoError(err)
+	assert.InDelta(3*radius*math.Sqrt2, ell.a, 1e-9)
+	assert.InDelta(radius*math.Sqrt2, ell.b, 1e-9)
+	assert.InDelta(math.Pi/4, ell.NormalizedAngle(), 1e-9)
+
+	testCases := []struct {
+		cov mat.Symmetric
+		c   float64
+	}{
+		{mat.NewSymDense(3, nil), 0.95},
+		{cov, 0},
+		{cov, 1.5},
+		{mat.NewSymDense(2, []float64{1.0, 0, 0, 0}), 0.95},
+		{mat.NewSymDense(2, []float64{1.0, 0, 0, -1.0}), 0.95},
+		{mat.NewSymDense(2, []float64{1.0, 1.0, 1.0, 1.0 - 1e-17}), 0.95},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewFromCovariance(0, 0, tc.cov, tc.c)
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
+
+func TestImpliedCovariance(t *testing.T) {
+	assert := assert.New(t)
+
+	cov := mat.NewSymDense(2, []float64{10.0, 3.0, 3.0, 2.0})
+	ell, err := NewFromCovariance(1.0, 2.0, cov, 0.9)
+	assert.NoError(err)
+
+	implied, err := ell.ImpliedCovariance(0.9)
+	assert.NoError(err)
+	assert.True(mat.EqualApprox(cov, implied, 1e-9))
+
+	// ellipses created from the geometric parameters
+	ell = &Ellipse{a: 2.0, b: 1.0}
+	implied, err = ell.ImpliedCovariance(CoverageProbability(1))
+	assert.NoError(err)
+	assert.True(mat.EqualApprox(mat.NewSymDense(2, []float64{4.0, 0, 0, 1.0}), implied, 1e-9))
+
+	_, err = ell.ImpliedCovariance(0)
+	assert.Error(err)
+}
+
+func TestBhattacharyya(t *testing.T) {
+	assert := assert.New(t)
+
+	e1, err := NewWithDataConfidence(gaussData(100, 0, 0, 1), 0.95)
+	assert.NoError(err)
+
+	e2, err := NewWithDataConfidence(gaussData(100, 5, 5, 2), 0.95)
+	assert.NoError(err)
+
+	d, err := e1.Bhattacharyya(e1)
+	assert.NoError(err)
+	assert.InDelta(0, d, 1e-9)
+	assert.InDelta(1, math.Exp(-d), 1e-9)
+
+	d, err = e1.Bhattacharyya(e2)
+	assert.NoError(err)
+	assert.True(d > 0)
+
+	ell, err := New(0, 0, 1, 2, 0)
+	assert.NoError(err)
+
+	_, err = e1.Bhattacharyya(ell)
+	assert.Error(err)
+
+	_, err = ell.Bhattacharyya(e1)
+	assert.Error(err)
+}
+
+func TestKLDivergence(t *testing.T) {
+	assert := assert.New(t)
+
+	e1, err := NewWithDataConfidence(gaussData(100, 0, 0, 1), 0.95)
+	assert.NoError(err)
+
+	e2, err := NewWithDataConfidence(gaussData(100, 5, 5, 2), 0.95)
+	assert.NoError(err)
+
+	d, err := e1.KLDivergence(e1)
+	assert.NoError(err)
+	assert.InDelta(0, d, 1e-9)
+
+	d, err = e1.KLDivergence(e2)
+	assert.NoError(err)
+	assert.True(d > 0)
+
+	d, err = e2.KLDivergence(e1)
+	assert.NoError(err)
+	assert.True(d > 0)
+
+	ell, err := New(0, 0, 1, 2, 0)
+	assert.NoError(err)
+
+	_, err = e1.KLDivergence(ell)
+	assert.Error(err)
+
+	_, err = ell.KLDivergence(e1)
+	assert.Error(err)
+}
+
+func TestMahalanobis(t *testing.T) {
+	assert := assert.New(t)
+
+	ell, err := NewFromCovariance(1.0, 2.0, mat.NewSymDense(2, []float64{10.0, 3.0, 3.0, 2.0}), 0.95)
+	assert.NoError(err)
+	radius, err := ConfidenceToRadius(0.95)
+	assert.NoError(err)
+
+	d, err := ell.Mahalanobis(1.0, 2.0)
+	assert.NoError(err)
+	assert.Zero(d)
+
+	// the ellipse boundary is radius Mahalanobis distance away from the mean
+	for _, theta := range []float64{0, 1.0, 2.0, 4.0} {
+		x, y := ell.PointAt(theta)
+		d, err := ell.Mahalanobis(x, y)
+		assert.NoError(err)
+		assert.InDelta(radius, d, 1e-9)
+	}
+
+	// Mahalanobis distance of the point is the norm of the point whitened by the covariance Cholesky factor
+	var chol mat.Cholesky
+	assert.True(chol.Factorize(mat.NewSymDense(2, []float64{10.0, 3.0, 3.0, 2.0})))
+	var l mat.TriDense
+	chol.LTo(&l)
+	var w mat.VecDense
+	assert.NoError(w.SolveVec(&l, mat.NewVecDense(2, []float64{3.0 - 1.0, -1.0 - 2.0})))
+	d, err = ell.Mahalanobis(3.0, -1.0)
+	assert.NoError(err)
+	assert.InDelta(mat.Norm(&w, 2), d, 1e-9)
+
+	d, err = (&Ellipse{a: 1.0, b: 1.0}).Mahalanobis(1.0, 1.0)
+	assert.Error(err)
+	assert.Zero(d)
+}
+
+func TestPValue(t *testing.T) {
+	assert := assert.New(t)
+
+	ell, err := NewFromCovariance(1.0, 2.0, mat.NewSymDense(2, []float64{10.0, 3.0, 3.0, 2.0}), 0.95)
+	assert.NoError(err)
+
+	p, err := ell.PValue(1.0, 2.0)
+	assert.NoError(err)
+	assert.InDelta(1.0, p, 1e-12)
+
+	// the ellipse boundary encloses confidence probability mass
+	for _, theta := range []float64{0, 1.0, 2.0, 4.0} {
+		x, y := ell.PointAt(theta)
+		p, err := ell.PValue(x, y)
+		assert.NoError(err)
+		assert.InDelta(0.05, p, 1e-9)
+	}
+
+	p, err = ell.PValue(100.0, -100.0)
+	assert.NoError(err)
+	assert.True(p < 1e-9)
+
+	p, err = (&Ellipse{a: 1.0, b: 1.0}).PValue(1.0, 1.0)
+	assert.Error(err)
+	assert.Zero(p)
+}
+
+func TestMahalanobisBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	ell, err := NewFromCovariance(1.0, 2.0, mat.NewSymDense(2, []float64{10.0, 3.0, 3.0, 2.0}), 0.95)
+	assert.NoError(err)
+
+	data := gaussData(100, 1.0, 2.0, 1)
+	dists, err := ell.MahalanobisBatch(data)
+	assert.NoError(err)
+	assert.Len(dists, 100)
+
+	for i, d := range dists {
+		exp, err := ell.Mahalanobis(data.At(i, 0), data.At(i, 1))
+		assert.NoError(err)
+		assert.InDelta(exp, d, 1e-9)
+	}
+
+	dists, err = ell.MahalanobisBatch(mat.NewDense(2, 1, nil))
+	assert.Error(err)
+	assert.Nil(dists)
+
+	dists, err = (&Ellipse{a: 1.0, b: 1.0}).MahalanobisBatch(data)
+	assert.Error(err)
+	assert.Nil(dists)
+}
+
+func BenchmarkMahalanobis(b *testing.B) {
+	ell, err := NewFromCovariance(1.0, 2.0, mat.NewSymDense(2, []float64{10.0, 3.0, 3.0, 2.0}), 0.95)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := gaussData(10000, 1.0, 2.0, 1)
+	rows, _ := data.Dims()
+
+	b.Run("Loop", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			dists := make([]float64, rows)
+			for i := range dists {
+				dists[i], _ = ell.Mahalanobis(data.At(i, 0), data.At(i, 1))
+			}
+		}
+	})
+
+	b.Run("Batch", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			if _, err := ell.MahalanobisBatch(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestFuse(t *testing.T) {
+	assert := assert.New(t)
+
+	e1, err := NewWithDataConfidence(gaussData(100, 1, 2, 1), 0.95)
+	assert.NoError(err)
+
+	// fusing identical distributions halves the covariance
+	fused, err := e1.Fuse(e1, 0.95)
+	assert.NoError(err)
+	assert.InDelta(e1.x, fused.x, 1e-9)
+	assert.InDelta(e1.y, fused.y, 1e-9)
+	assert.InDelta(e1.a/math.Sqrt2, fused.a, 1e-9)
+	assert.InDelta(e1.b/math.Sqrt2, fused.b, 1e-9)
+	assert.InDelta(e1.angle, fused.angle, 1e-9)
+	assert.True(fused.Area() < e1.Area())
+
+	cov, err := e1.ImpliedCovariance(0.95)
+	assert.NoError(err)
+	fusedCov, err := fused.ImpliedCovariance(0.95)
+	assert.NoError(err)
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			assert.InDelta(cov.At(i, j)/2, fusedCov.At(i, j), 1e-9)
+		}
+	}
+
+	// fusing distributions of the same covariance yields the means midpoint
+	e2, err := NewFromCovariance(e1.x+4, e1.y-2, cov, 0.95)
+	assert.NoError(err)
+	fused, err = e1.Fuse(e2, 0.95)
+	assert.NoError(err)
+	assert.InDelta(e1.x+2, fused.x, 1e-9)
+	assert.InDelta(e1.y-1, fused.y, 1e-9)
+
+	ell, err := New(0, 0, 1, 2, 0)
+	assert.NoError(err)
+
+	_, err = e1.Fuse(ell, 0.95)
+	assert.Error(err)
+
+	_, err = ell.Fuse(e1, 0.95)
+	assert.Error(err)
+
+	_, err = e1.Fuse(e1, 1.0)
+	assert.Error(err)
+}
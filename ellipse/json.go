@@ -0,0 +1,80 @@
+package ellipse
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ellipseJSON is the JSON representation of Ellipse
+type ellipseJSON struct {
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	A     float64 `json:"a"`
+	B     float64 `json:"b"`
+	Angle float64 `json:"angle"`
+	Scale float64 `json:"scale,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler interface.
+// The covariance metadata is only encoded if the ellipse carries it.
+// MarshalJSON has a value receiver so that Ellipse values, not just pointers, are encoded.
+func (e Ellipse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ellipseJSON{
+		X:     e.x,
+		Y:     e.y,
+		A:     e.a,
+		B:     e.b,
+		Angle: e.angle,
+		Scale: e.scale,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+// It returns error if either of the decoded ellipse axes is not positive or if the covariance metadata is negative.
+func (e *Ellipse) UnmarshalJSON(data []byte) error {
+	var v ellipseJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	ell, err := New(v.X, v.Y, v.A, v.B, v.Angle)
+	if err != nil {
+		return err
+	}
+
+	if v.Scale < 0 {
+		return fmt.Errorf("Invalid ellipse covariance metadata: %.2f", v.Scale)
+	}
+	ell.scale = v.Scale
+
+	*e = *ell
+
+	return nil
+}
+
+// Scene is a collection of ellipses which can be encoded to and decoded from JSON.
+type Scene struct {
+	Ellipses []*Ellipse `json:"ellipses"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+// It returns error if any of the decoded scene ellipses is missing or invalid.
+func (s *Scene) UnmarshalJSON(data []byte) error {
+	// scene is an alias type which prevents UnmarshalJSON recursion
+	type scene Scene
+
+	var v scene
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	for i, e := range v.Ellipses {
+		if e == nil {
+			return fmt.Errorf("Missing scene ellipse: %d", i)
+		}
+	}
+
+	*s = Scene(v)
+
+	return nil
+}
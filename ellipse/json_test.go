@@ -0,0 +1,98 @@
+package ellipse
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEllipseJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: -2.0, a: 3.0, b: 1.5, angle: math.Pi / 3, scale: 2.0}
+
+	data, err := json.Marshal(ell)
+	assert.NoError(err)
+
+	var dec Ellipse
+	assert.NoError(json.Unmarshal(data, &dec))
+	assert.Equal(*ell, dec)
+
+	// ellipse without covariance metadata omits it
+	data, err = json.Marshal(&Ellipse{a: 1.0, b: 1.0})
+	assert.NoError(err)
+	assert.JSONEq(`{"x":0,"y":0,"a":1,"b":1,"angle":0}`, string(data))
+
+	testCases := []string{
+		`{"x":0,"y":0,"a":0,"b":1,"angle":0}`,
+		`{"x":0,"y":0,"a":1,"b":-1,"angle":0}`,
+		`{"x":0,"y":0,"a":1,"b":1,"angle":0,"scale":-1}`,
+		`{"x":"foo"}`,
+	}
+
+	for _, tc := range testCases {
+		var e Ellipse
+		assert.Error(json.Unmarshal([]byte(tc), &e))
+	}
+}
+
+func TestEllipseValueJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{x: 1.0, y: -2.0, a: 3.0, b: 1.5, angle: math.Pi / 5, scale: 5.99}
+
+	data, err := json.Marshal(ell)
+	assert.NoError(err)
+	ptrData, err := json.Marshal(&ell)
+	assert.NoError(err)
+	assert.JSONEq(string(ptrData), string(data))
+
+	var decoded Ellipse
+	assert.NoError(json.Unmarshal(data, &decoded))
+	assert.Equal(ell, decoded)
+
+	ellipses := []Ellipse{ell, {x: 0, y: 1.0, a: 2.0, b: 2.0}}
+	data, err = json.Marshal(ellipses)
+	assert.NoError(err)
+
+	var decodedEllipses []Ellipse
+	assert.NoError(json.Unmarshal(data, &decodedEllipses))
+	assert.Equal(ellipses, decodedEllipses)
+
+	// nil ellipse pointer is encoded as null
+	data, err = json.Marshal((*Ellipse)(nil))
+	assert.NoError(err)
+	assert.Equal("null", string(data))
+}
+
+func TestSceneJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	scene := &Scene{
+		Ellipses: []*Ellipse{
+			{a: 1.0, b: 1.0},
+			{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 4},
+			{x: -1.0, y: 4.0, a: 2.0, b: 5.0, angle: 1.0, scale: 5.99},
+		},
+	}
+
+	data, err := json.Marshal(scene)
+	assert.NoError(err)
+
+	var dec Scene
+	assert.NoError(json.Unmarshal(data, &dec))
+	assert.Equal(*scene, dec)
+
+	testCases := []string{
+		`{"ellipses":[{"x":0,"y":0,"a":1,"b":1,"angle":0},{"x":0,"y":0,"a":-1,"b":1,"angle":0}]}`,
+		`{"ellipses":[{"x":0,"y":0,"a":1,"b":1,"angle":0},null]}`,
+		`{"ellipses":{}}`,
+	}
+
+	for _, tc := range testCases {
+		var s Scene
+		assert.Error(json.Unmarshal([]byte(tc), &s))
+	}
+}
@@ -0,0 +1,46 @@
+package ellipse
+
+import "math"
+
+// Option configures Ellipse created by NewOpts.
+type Option func(*Ellipse)
+
+// WithCenter sets the Ellipse origin to [x,y].
+func WithCenter(x, y float64) Option {
+	return func(e *Ellipse) {
+		e.x, e.y = x, y
+	}
+}
+
+// WithAxes sets the lengths of the Ellipse semi-major/minor axis.
+func WithAxes(a, b float64) Option {
+	return func(e *Ellipse) {
+		e.a, e.b = a, b
+	}
+}
+
+// WithAngle sets the Ellipse rotation angle in radians.
+func WithAngle(rad float64) Option {
+	return func(e *Ellipse) {
+		e.angle = rad
+	}
+}
+
+// WithAngleDeg sets the Ellipse rotation angle in degrees.
+func WithAngleDeg(deg float64) Option {
+	return func(e *Ellipse) {
+		e.angle = deg * math.Pi / 180
+	}
+}
+
+// NewOpts creates new Ellipse configured by opts.
+// Unless configured otherwise, the created Ellipse is a unit circle centered at the origin.
+// It returns error if either of the configured axis is not positive.
+func NewOpts(opts ...Option) (*Ellipse, error) {
+	e := &Ellipse{a: 1.0, b: 1.0}
+	for _, apply := range opts {
+		apply(e)
+	}
+
+	return New(e.x, e.y, e.a, e.b, e.angle)
+}
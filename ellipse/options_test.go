@@ -0,0 +1,37 @@
+package ellipse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOpts(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		opts []Option
+		exp  *Ellipse
+		err  bool
+	}{
+		{nil, &Ellipse{a: 1.0, b: 1.0}, false},
+		{[]Option{WithCenter(1.0, 2.0)}, &Ellipse{x: 1.0, y: 2.0, a: 1.0, b: 1.0}, false},
+		{[]Option{WithAxes(3.0, 4.0)}, &Ellipse{a: 3.0, b: 4.0}, false},
+		{[]Option{WithAngle(math.Pi)}, &Ellipse{a: 1.0, b: 1.0, angle: math.Pi}, false},
+		{[]Option{WithAngleDeg(180)}, &Ellipse{a: 1.0, b: 1.0, angle: math.Pi}, false},
+		{[]Option{WithAxes(0, 1.0)}, nil, true},
+		{[]Option{WithAxes(1.0, -2.0)}, nil, true},
+	}
+
+	for _, tc := range testCases {
+		ell, err := NewOpts(tc.opts...)
+		if !tc.err {
+			assert.NoError(err)
+			assert.Equal(tc.exp, ell)
+			continue
+		}
+		assert.Error(err)
+		assert.Nil(ell)
+	}
+}
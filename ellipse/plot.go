@@ -0,0 +1,223 @@
+package ellipse
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// polygonSize is the number of boundary points of the polygons returned by NestedConfidencePolygons
+const polygonSize = 100
+
+// LatusRectumLine returns plotter.Line which can be used to plot the ellipse latus rectum
+// i.e. the chord through one of the ellipse foci perpendicular to the ellipse major axis.
+func (e *Ellipse) LatusRectumLine() (*plotter.Line, error) {
+	_, _, angle := e.majorAxis()
+	f, _ := e.Foci()
+	l := e.SemiLatusRectum()
+
+	// the latus rectum is perpendicular to the major axis
+	sin, cos := math.Sincos(angle)
+	xys := plotter.XYs{
+		{X: f.X + l*sin, Y: f.Y - l*cos},
+		{X: f.X - l*sin, Y: f.Y + l*cos},
+	}
+
+	return plotter.NewLine(xys)
+}
+
+// AxesLines returns two plotter.Line which can be used to plot the ellipse major and minor axis
+// i.e. the segments between the ellipse vertices which pass through the ellipse center.
+func (e *Ellipse) AxesLines() (major, minor *plotter.Line, err error) {
+	ma, mi, angle := e.majorAxis()
+	sin, cos := math.Sincos(angle)
+
+	major, err = plotter.NewLine(plotter.XYs{
+		{X: e.x - ma*cos, Y: e.y - ma*sin},
+		{X: e.x + ma*cos, Y: e.y + ma*sin},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// minor axis is perpendicular to the major axis
+	minor, err = plotter.NewLine(plotter.XYs{
+		{X: e.x + mi*sin, Y: e.y - mi*cos},
+		{X: e.x - mi*sin, Y: e.y + mi*cos},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return major, minor, nil
+}
+
+// FeatureScatter returns plotter.Scatter which can be used to plot the ellipse center and both of its foci.
+func (e *Ellipse) FeatureScatter() (*plotter.Scatter, error) {
+	f1, f2 := e.Foci()
+	xys := plotter.XYs{{X: e.x, Y: e.y}, f1, f2}
+
+	return plotter.NewScatter(xys)
+}
+
+// PartitionScatter returns two plotter.Scatter which can be used to plot the points stored in the first two columns
+// of data which lie inside and outside of the ellipse, respectively.
+// It returns error if data has less than 2 columns or if any of the data points contains a NaN or Infinity.
+func (e *Ellipse) PartitionScatter(data mat.Matrix) (inside, outside *plotter.Scatter, err error) {
+	labels, err := e.LabelPoints(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var in, out plotter.XYs
+	for i, label := range labels {
+		xy := plotter.XY{X: data.At(i, 0), Y: data.At(i, 1)}
+		if label {
+			in = append(in, xy)
+			continue
+		}
+		out = append(out, xy)
+	}
+
+	if inside, err = plotter.NewScatter(in); err != nil {
+		return nil, nil, err
+	}
+
+	if outside, err = plotter.NewScatter(out); err != nil {
+		return nil, nil, err
+	}
+
+	return inside, outside, nil
+}
+
+// CanvasPadding returns the horizontal and vertical padding which must be added to each side of the canvas
+// of the given width and height spanning the plot data range [xmin, xmax] horizontally and [ymin, ymax] vertically
+// so that the ellipse is not clipped by the canvas.
+// The padding is the largest extent of the ellipse bounding box beyond the data range on either side
+// scaled to the canvas and it is zero in the directions in which the ellipse fits into the data range.
+// It panics if the data range is empty in either direction.
+func (e *Ellipse) CanvasPadding(xmin, xmax, ymin, ymax float64, width, height vg.Length) (padX, padY vg.Length) {
+	if !(xmin < xmax) || !(ymin < ymax) {
+		panic("Invalid data range")
+	}
+
+	minX, minY, maxX, maxY := e.BoundingBox()
+
+	// extent of the bounding box beyond the data range
+	dx := math.Max(math.Max(xmin-minX, maxX-xmax), 0)
+	dy := math.Max(math.Max(ymin-minY, maxY-ymax), 0)
+
+	return vg.Length(dx/(xmax-xmin)) * width, vg.Length(dy/(ymax-ymin)) * height
+}
+
+// FilledLinePoints returns plotter.Polygon filled with fill color and plotter.Line which can be used
+// to plot the ellipse interior and its outline using size number of the ellipse points.
+// The polygon has no outline of its own, so semi-transparent fill colors can be used to shade the ellipse.
+// It returns error if at least one of the ellipse data points contains a NaN or Infinity.
+func (e *Ellipse) FilledLinePoints(size int, fill color.Color) (*plotter.Polygon, *plotter.Line, error) {
+	xys := e.boundary(size)
+
+	poly, err := plotter.NewPolygon(xys)
+	if err != nil {
+		return nil, nil, err
+	}
+	poly.Color = fill
+	poly.LineStyle.Width = 0
+
+	line, err := plotter.NewLine(xys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return poly, line, nil
+}
+
+// NestedConfidencePolygons returns filled polygons of the data confidence ellipses, one for every confidence level in levels.
+// Each polygon is filled with the color returned by cmap for its confidence level.
+// The polygons are ordered from the largest to the smallest confidence level, so when they are added to the plot
+// in the returned order the smaller confidence regions are drawn on top of the larger ones.
+// The data principal components are calculated only once and shared by all the confidence levels.
+// It returns error if levels is empty, cmap is nil, any of the levels is not in (0,1) interval
+// or if the confidence model could not be created from the supplied data.
+func NestedConfidencePolygons(data mat.Matrix, levels []float64, cmap func(level float64) color.Color) ([]*plotter.Polygon, error) {
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("Missing confidence levels")
+	}
+
+	if cmap == nil {
+		return nil, fmt.Errorf("Missing color map")
+	}
+
+	m, err := NewConfidenceModel(data)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]float64, len(levels))
+	copy(sorted, levels)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+
+	polys := make([]*plotter.Polygon, len(sorted))
+	for i, level := range sorted {
+		e, err := m.Ellipse(level)
+		if err != nil {
+			return nil, err
+		}
+
+		poly, _, err := e.FilledLinePoints(polygonSize, cmap(level))
+		if err != nil {
+			return nil, err
+		}
+		polys[i] = poly
+	}
+
+	return polys, nil
+}
+
+// MatplotlibPatch returns the parameters of matplotlib.patches.Ellipse which reproduces the ellipse in Python matplotlib:
+// the ellipse center xy, the full lengths of the ellipse axes width and height and the rotation angle angleDeg.
+// Unlike the ellipse which stores the semi-axes, matplotlib expects the full axes lengths, so width is 2*a and height is 2*b.
+// Matplotlib also expects the rotation angle in degrees rather than in radians: the angle is rotated counter-clockwise
+// from X axis to the width axis just like the ellipse angle is rotated to the a semi-axis.
+//
+// For more information see: https://matplotlib.org/stable/api/_as_gen/matplotlib.patches.Ellipse.html
+func (e *Ellipse) MatplotlibPatch() (xy [2]float64, width, height, angleDeg float64) {
+	return [2]float64{e.x, e.y}, 2 * e.a, 2 * e.b, e.angle * 180 / math.Pi
+}
+
+// outline is plot.Plotter which plots the ellipse outline.
+type outline struct {
+	e    *Ellipse
+	size int
+}
+
+// Plotter returns plot.Plotter which plots the ellipse outline using size number of the ellipse points.
+// The returned plotter implements plot.DataRanger, so the plot axes are adjusted to contain the whole ellipse.
+// Nothing is plotted if at least one of the ellipse points contains a NaN or Infinity.
+func (e *Ellipse) Plotter(size int) plot.Plotter {
+	return &outline{e: e, size: size}
+}
+
+// Plot implements plot.Plotter interface.
+func (o *outline) Plot(c draw.Canvas, p *plot.Plot) {
+	line, _, err := o.e.LinePoints(o.size)
+	if err != nil {
+		return
+	}
+
+	line.Plot(c, p)
+}
+
+// DataRange implements plot.DataRanger interface.
+func (o *outline) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin, ymin, xmax, ymax = o.e.BoundingBox()
+
+	return xmin, xmax, ymin, ymax
+}
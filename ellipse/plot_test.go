@@ -0,0 +1,276 @@
+package ellipse
+
+import (
+	"bytes"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestLatusRectumLine(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []*Ellipse{
+		{x: 1.0, y: 1.0, a: 5.0, b: 3.0},
+		{a: 3.0, b: 5.0, angle: math.Pi / 5},
+		{x: -2.0, y: 3.0, a: 4.0, b: 1.0, angle: 2.0},
+	}
+
+	for _, ell := range testCases {
+		line, err := ell.LatusRectumLine()
+		assert.NoError(err)
+		assert.Len(line.XYs, 2)
+
+		p1, p2 := line.XYs[0], line.XYs[1]
+		assert.InDelta(2*ell.SemiLatusRectum(), math.Hypot(p2.X-p1.X, p2.Y-p1.Y), 1e-9)
+		// the line endpoints lie on the ellipse
+		assert.InDelta(0, ell.Implicit(p1.X, p1.Y), 1e-9)
+		assert.InDelta(0, ell.Implicit(p2.X, p2.Y), 1e-9)
+		// the line passes through the focus
+		f, _ := ell.Foci()
+		assert.InDelta(f.X, (p1.X+p2.X)/2, 1e-9)
+		assert.InDelta(f.Y, (p1.Y+p2.Y)/2, 1e-9)
+	}
+}
+
+func TestAxesLines(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []*Ellipse{
+		{x: 1.0, y: 1.0, a: 5.0, b: 3.0},
+		{a: 3.0, b: 5.0, angle: math.Pi / 5},
+		{x: -2.0, y: 3.0, a: 4.0, b: 1.0, angle: 2.0},
+	}
+
+	for _, ell := range testCases {
+		major, minor, err := ell.AxesLines()
+		assert.NoError(err)
+		assert.Len(major.XYs, 2)
+		assert.Len(minor.XYs, 2)
+
+		p1, p2 := major.XYs[0], major.XYs[1]
+		q1, q2 := minor.XYs[0], minor.XYs[1]
+		assert.InDelta(2*math.Max(ell.a, ell.b), math.Hypot(p2.X-p1.X, p2.Y-p1.Y), 1e-9)
+		assert.InDelta(2*math.Min(ell.a, ell.b), math.Hypot(q2.X-q1.X, q2.Y-q1.Y), 1e-9)
+		// both lines pass through the center
+		assert.InDelta(ell.x, (p1.X+p2.X)/2, 1e-9)
+		assert.InDelta(ell.y, (p1.Y+p2.Y)/2, 1e-9)
+		assert.InDelta(ell.x, (q1.X+q2.X)/2, 1e-9)
+		assert.InDelta(ell.y, (q1.Y+q2.Y)/2, 1e-9)
+		// the line endpoints are the ellipse vertices
+		vertices := plotter.XYs{p1, p2, q1, q2}
+		for _, p := range vertices {
+			assert.InDelta(0, ell.Implicit(p.X, p.Y), 1e-9)
+		}
+		// the axes are perpendicular
+		assert.InDelta(0, (p2.X-p1.X)*(q2.X-q1.X)+(p2.Y-p1.Y)*(q2.Y-q1.Y), 1e-9)
+	}
+}
+
+func TestFeatureScatter(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 1.0, a: 5.0, b: 3.0}
+
+	scatter, err := ell.FeatureScatter()
+	assert.NoError(err)
+	assert.Equal(3, scatter.Len())
+
+	exp := [][2]float64{{1.0, 1.0}, {5.0, 1.0}, {-3.0, 1.0}}
+	for i, xy := range exp {
+		x, y := scatter.XY(i)
+		assert.InDelta(xy[0], x, 1e-9)
+		assert.InDelta(xy[1], y, 1e-9)
+	}
+}
+
+func TestPartitionScatter(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 6.0, b: 2.0, angle: math.Pi / 4}
+	data := gaussData(500, 1.0, 2.0, 7)
+
+	inside, outside, err := ell.PartitionScatter(data)
+	assert.NoError(err)
+	assert.Equal(500, inside.Len()+outside.Len())
+
+	count, err := ell.CountInside(data)
+	assert.NoError(err)
+	assert.Equal(count, inside.Len())
+
+	for i := 0; i < inside.Len(); i++ {
+		assert.True(ell.Contains(inside.XY(i)))
+	}
+	for i := 0; i < outside.Len(); i++ {
+		assert.False(ell.Contains(outside.XY(i)))
+	}
+
+	inside, outside, err = ell.PartitionScatter(mat.NewDense(2, 1, []float64{1.0, 2.0}))
+	assert.Error(err)
+	assert.Nil(inside)
+	assert.Nil(outside)
+}
+
+func TestCanvasPadding(t *testing.T) {
+	assert := assert.New(t)
+
+	width, height := 4*vg.Inch, 2*vg.Inch
+
+	// rotated circle fits into its own unrotated extent
+	circle := &Ellipse{x: 1.0, y: 1.0, a: 2.0, b: 2.0, angle: math.Pi / 4}
+	padX, padY := circle.CanvasPadding(-1.0, 3.0, -1.0, 3.0, width, height)
+	assert.Zero(padX)
+	assert.Zero(padY)
+
+	// the ellipse extends 2 units beyond the 4 units wide range and 1 unit beyond the 2 units high range
+	ell := &Ellipse{a: 4.0, b: 2.0}
+	padX, padY = ell.CanvasPadding(-2.0, 2.0, -1.0, 1.0, width, height)
+	assert.InDelta(float64(2*vg.Inch), float64(padX), 1e-9)
+	assert.InDelta(float64(vg.Inch), float64(padY), 1e-9)
+
+	// ellipse rotated by pi/2 spans [-2, 2] horizontally and [-4, 4] vertically
+	ell = &Ellipse{a: 4.0, b: 2.0, angle: math.Pi / 2}
+	padX, padY = ell.CanvasPadding(-4.0, 4.0, -2.0, 2.0, width, height)
+	assert.Zero(padX)
+	assert.InDelta(float64(vg.Inch), float64(padY), 1e-9)
+
+	// the padding is determined by the side on which the ellipse extends the most
+	circle = &Ellipse{a: 2.0, b: 2.0}
+	padX, padY = circle.CanvasPadding(0, 4.0, -3.0, 2.5, width, height)
+	assert.InDelta(float64(2*vg.Inch), float64(padX), 1e-9)
+	assert.Zero(padY)
+
+	assert.Panics(func() { ell.CanvasPadding(1.0, 1.0, 0, 1.0, width, height) })
+	assert.Panics(func() { ell.CanvasPadding(0, 1.0, 2.0, 1.0, width, height) })
+}
+
+func TestFilledLinePoints(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 6}
+	fill := color.NRGBA{R: 255, A: 64}
+
+	poly, line, err := ell.FilledLinePoints(50, fill)
+	assert.NoError(err)
+	assert.Equal(fill, poly.Color)
+	assert.Zero(poly.LineStyle.Width)
+	assert.Len(poly.XYs, 1)
+	assert.Len(poly.XYs[0], 50)
+	assert.Equal(line.XYs, poly.XYs[0])
+
+	_, points, err := ell.LinePoints(50)
+	assert.NoError(err)
+	assert.Equal(points.XYs, line.XYs)
+
+	ell = &Ellipse{a: math.Inf(1), b: 1.0}
+	poly, line, err = ell.FilledLinePoints(50, fill)
+	assert.Error(err)
+	assert.Nil(poly)
+	assert.Nil(line)
+}
+
+func TestNestedConfidencePolygons(t *testing.T) {
+	assert := assert.New(t)
+
+	data := gaussData(100, 1.0, 2.0, 1)
+	levels := []float64{0.5, 0.99, 0.9}
+	cmap := func(level float64) color.Color {
+		return color.Gray{Y: uint8(255 * level)}
+	}
+
+	polys, err := NestedConfidencePolygons(data, levels, cmap)
+	assert.NoError(err)
+	assert.Len(polys, len(levels))
+
+	// the polygons are ordered from the largest confidence level
+	for i, level := range []float64{0.99, 0.9, 0.5} {
+		assert.Equal(cmap(level), polys[i].Color)
+		assert.Zero(polys[i].LineStyle.Width)
+
+		e, err := NewWithDataConfidence(data, level)
+		assert.NoError(err)
+		_, points, err := e.LinePoints(polygonSize)
+		assert.NoError(err)
+		assert.Len(polys[i].XYs, 1)
+		assert.Equal(points.XYs, polys[i].XYs[0])
+	}
+
+	// the supplied levels are not modified
+	assert.Equal([]float64{0.5, 0.99, 0.9}, levels)
+
+	testCases := []struct {
+		levels []float64
+		cmap   func(float64) color.Color
+	}{
+		{nil, cmap},
+		{[]float64{}, cmap},
+		{levels, nil},
+		{[]float64{0.5, 1.0}, cmap},
+		{[]float64{0, 0.5}, cmap},
+	}
+
+	for _, tc := range testCases {
+		polys, err := NestedConfidencePolygons(data, tc.levels, tc.cmap)
+		assert.Error(err)
+		assert.Nil(polys)
+	}
+
+	_, err = NestedConfidencePolygons(mat.NewDense(3, 1, []float64{1.0, 2.0, 3.0}), levels, cmap)
+	assert.Error(err)
+}
+
+func TestMatplotlibPatch(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		ell      *Ellipse
+		width    float64
+		height   float64
+		angleDeg float64
+	}{
+		{&Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}, 6.0, 2.0, 60.0},
+		{&Ellipse{x: -1.0, y: 0, a: 1.0, b: 4.0, angle: 0}, 2.0, 8.0, 0},
+		{&Ellipse{x: 0, y: 5.0, a: 2.0, b: 2.0, angle: -math.Pi / 2}, 4.0, 4.0, -90.0},
+	}
+
+	for _, tc := range testCases {
+		xy, width, height, angleDeg := tc.ell.MatplotlibPatch()
+		assert.Equal([2]float64{tc.ell.x, tc.ell.y}, xy)
+		assert.Equal(tc.width, width)
+		assert.Equal(tc.height, height)
+		assert.InDelta(tc.angleDeg, angleDeg, 1e-12)
+		assert.Equal(tc.ell.Diameter(), math.Max(width, height))
+	}
+}
+
+func TestPlotter(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 6}
+
+	p, err := plot.New()
+	assert.NoError(err)
+
+	pl := ell.Plotter(100)
+	p.Add(pl)
+
+	_, ok := pl.(plot.DataRanger)
+	assert.True(ok)
+
+	minX, minY, maxX, maxY := ell.BoundingBox()
+	assert.True(p.X.Min <= minX && p.X.Max >= maxX)
+	assert.True(p.Y.Min <= minY && p.Y.Max >= maxY)
+
+	c, err := p.WriterTo(4*vg.Inch, 4*vg.Inch, "png")
+	assert.NoError(err)
+	var buf bytes.Buffer
+	_, err = c.WriteTo(&buf)
+	assert.NoError(err)
+	assert.NotZero(buf.Len())
+}
@@ -0,0 +1,180 @@
+package ellipse
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// alignTol is the tolerance of the ellipse angle used to decide whether the ellipse is axis aligned
+const alignTol = 1e-9
+
+// Rasterize returns the pixels of width x height pixel grid which the ellipse boundary passes through.
+// World coordinates are mapped into the pixel grid by dividing them by scale: the world origin
+// maps to the bottom-left pixel of the grid and world Y axis points up, i.e. the world point [x,y]
+// maps to the pixel [x/scale, height-1-y/scale]. Each pixel appears in the result only once and
+// the pixels which fall outside of the grid are discarded.
+// Axis aligned ellipses are rasterized using the midpoint ellipse algorithm; rotated ellipses
+// are rasterized by connecting points sampled from the ellipse boundary using Bresenham's line algorithm.
+// It returns nil if the grid is empty or scale is not positive.
+func (e *Ellipse) Rasterize(width, height int, scale float64) []image.Point {
+	if width <= 0 || height <= 0 || !(scale > 0) {
+		return nil
+	}
+
+	r := &raster{
+		bounds: image.Rect(0, 0, width, height),
+		seen:   make(map[image.Point]bool),
+	}
+
+	cx, cy := e.x/scale, float64(height-1)-e.y/scale
+	rx, ry := math.Round(e.a/scale), math.Round(e.b/scale)
+
+	angle := e.NormalizedAngle()
+	if math.Abs(angle-math.Pi/2) < alignTol {
+		rx, ry = ry, rx
+	}
+
+	if (angle < alignTol || math.Abs(angle-math.Pi/2) < alignTol) && rx > 0 && ry > 0 {
+		r.midpoint(int(math.Round(cx)), int(math.Round(cy)), int(rx), int(ry))
+		return r.points
+	}
+
+	// sample enough boundary points so the consecutive points are at most a pixel apart
+	size := int(math.Ceil(2*e.Perimeter()/scale)) + 1
+	if size < 4 {
+		size = 4
+	}
+	points := e.boundary(size)
+
+	pixel := func(i int) image.Point {
+		x, y := points[i].X/scale, float64(height-1)-points[i].Y/scale
+		return image.Pt(int(math.Round(x)), int(math.Round(y)))
+	}
+	for i := 0; i < len(points)-1; i++ {
+		r.line(pixel(i), pixel(i+1))
+	}
+
+	return r.points
+}
+
+// RasterizeFilled returns width x height grayscale mask of the ellipse with the pixels inside the ellipse
+// set to 255 and the pixels outside of it set to 0. The pixels are mapped into world coordinates
+// the same way as in Rasterize, i.e. the pixel [i,j] maps to the world point [i*scale, (height-1-j)*scale].
+// It returns nil if the grid is empty or scale is not positive.
+func (e *Ellipse) RasterizeFilled(width, height int, scale float64) *image.Gray {
+	if width <= 0 || height <= 0 || !(scale > 0) {
+		return nil
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for j := 0; j < height; j++ {
+		y := float64(height-1-j) * scale
+		for i := 0; i < width; i++ {
+			if e.Contains(float64(i)*scale, y) {
+				img.SetGray(i, j, color.Gray{Y: 255})
+			}
+		}
+	}
+
+	return img
+}
+
+// raster collects unique pixels within bounds
+type raster struct {
+	bounds image.Rectangle
+	seen   map[image.Point]bool
+	points []image.Point
+}
+
+// plot adds the pixel [x,y] to raster if it lies within raster bounds and has not been added already.
+func (r *raster) plot(x, y int) {
+	p := image.Pt(x, y)
+	if !p.In(r.bounds) || r.seen[p] {
+		return
+	}
+	r.seen[p] = true
+	r.points = append(r.points, p)
+}
+
+// line rasterizes the line segment from p0 to p1 using Bresenham's line algorithm.
+//
+// For more information see: https://en.wikipedia.org/wiki/Bresenham%27s_line_algorithm
+func (r *raster) line(p0, p1 image.Point) {
+	dx, sx := p1.X-p0.X, 1
+	if dx < 0 {
+		dx, sx = -dx, -1
+	}
+	dy, sy := p1.Y-p0.Y, 1
+	if dy < 0 {
+		dy, sy = -dy, -1
+	}
+
+	x, y := p0.X, p0.Y
+	d := dx - dy
+	for {
+		r.plot(x, y)
+		if x == p1.X && y == p1.Y {
+			return
+		}
+		d2 := 2 * d
+		if d2 > -dy {
+			d -= dy
+			x += sx
+		}
+		if d2 < dx {
+			d += dx
+			y += sy
+		}
+	}
+}
+
+// midpoint rasterizes an axis aligned ellipse with center [cx,cy] and semi-axes rx and ry
+// using the midpoint ellipse algorithm.
+//
+// For more information see: https://en.wikipedia.org/wiki/Midpoint_circle_algorithm
+func (r *raster) midpoint(cx, cy, rx, ry int) {
+	plot4 := func(x, y int) {
+		r.plot(cx+x, cy+y)
+		r.plot(cx-x, cy+y)
+		r.plot(cx+x, cy-y)
+		r.plot(cx-x, cy-y)
+	}
+
+	rx2, ry2 := float64(rx*rx), float64(ry*ry)
+	x, y := 0, ry
+	px, py := 0.0, 2*rx2*float64(y)
+
+	plot4(x, y)
+
+	// region 1: the slope of the boundary is less than 1
+	p := ry2 - rx2*float64(ry) + rx2/4
+	for px < py {
+		x++
+		px += 2 * ry2
+		if p < 0 {
+			p += ry2 + px
+		} else {
+			y--
+			py -= 2 * rx2
+			p += ry2 + px - py
+		}
+		plot4(x, y)
+	}
+
+	// region 2: the slope of the boundary is greater than 1
+	fx, fy := float64(x)+0.5, float64(y-1)
+	p = ry2*fx*fx + rx2*fy*fy - rx2*ry2
+	for y > 0 {
+		y--
+		py -= 2 * rx2
+		if p > 0 {
+			p += rx2 - py
+		} else {
+			x++
+			px += 2 * ry2
+			p += rx2 - py + px
+		}
+		plot4(x, y)
+	}
+}
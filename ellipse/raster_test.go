@@ -0,0 +1,86 @@
+package ellipse
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRasterize(t *testing.T) {
+	assert := assert.New(t)
+
+	// circle with radius of 5 pixels centered in the 21x21 grid
+	ell := &Ellipse{x: 10.0, y: 10.0, a: 5.0, b: 5.0}
+	pixels := ell.Rasterize(21, 21, 1.0)
+	assert.NotEmpty(pixels)
+
+	set := make(map[image.Point]bool)
+	for _, p := range pixels {
+		assert.False(set[p])
+		set[p] = true
+	}
+
+	for _, p := range []image.Point{{15, 10}, {5, 10}, {10, 15}, {10, 5}} {
+		assert.True(set[p])
+	}
+
+	for p := range set {
+		assert.True(set[image.Pt(20-p.X, p.Y)])
+		assert.True(set[image.Pt(p.X, 20-p.Y)])
+		assert.True(set[image.Pt(20-p.X, 20-p.Y)])
+		assert.InDelta(5.0, math.Hypot(float64(p.X-10), float64(p.Y-10)), 1.0)
+	}
+
+	// the same circle at a different scale
+	ell = &Ellipse{x: 1.0, y: 1.0, a: 0.5, b: 0.5}
+	assert.ElementsMatch(pixels, ell.Rasterize(21, 21, 0.1))
+
+	// rotated ellipse pixels are close to the boundary
+	ell = &Ellipse{x: 20.0, y: 15.0, a: 12.0, b: 5.0, angle: math.Pi / 6}
+	pixels = ell.Rasterize(40, 30, 1.0)
+	assert.NotEmpty(pixels)
+	for _, p := range pixels {
+		x, y := float64(p.X), float64(29-p.Y)
+		assert.True(math.Abs(ell.Implicit(x, y)) < 0.25)
+	}
+
+	// pixels outside of the grid are discarded
+	ell = &Ellipse{a: 5.0, b: 5.0}
+	for _, p := range ell.Rasterize(21, 21, 1.0) {
+		assert.True(p.In(image.Rect(0, 0, 21, 21)))
+	}
+
+	assert.Nil(ell.Rasterize(0, 21, 1.0))
+	assert.Nil(ell.Rasterize(21, 21, 0))
+}
+
+func TestRasterizeFilled(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 5.0, y: 4.0, a: 4.0, b: 2.0, angle: math.Pi / 5}
+	scale := 0.05
+
+	img := ell.RasterizeFilled(200, 160, scale)
+	assert.NotNil(img)
+	assert.Equal(image.Rect(0, 0, 200, 160), img.Bounds())
+
+	white := 0
+	for _, v := range img.Pix {
+		switch v {
+		case 255:
+			white++
+		default:
+			assert.Zero(v)
+		}
+	}
+	assert.InEpsilon(ell.Area()/(scale*scale), float64(white), 0.01)
+
+	// center pixel is inside, corner pixel is outside
+	assert.Equal(uint8(255), img.GrayAt(100, 79).Y)
+	assert.Equal(uint8(0), img.GrayAt(0, 0).Y)
+
+	assert.Nil(ell.RasterizeFilled(200, 0, scale))
+	assert.Nil(ell.RasterizeFilled(200, 160, -1))
+}
@@ -0,0 +1,47 @@
+package ellipse
+
+import (
+	"fmt"
+	"io"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+)
+
+// WritePNG plots the ellipse line with size number of points and writes it as PNG image to w.
+// The width and height of the image are determined by width and height.
+// It returns error if the ellipse line could not be plotted or written to w.
+func (e *Ellipse) WritePNG(w io.Writer, width, height vg.Length, size int) error {
+	return e.WriteImage(w, "png", width, height, size)
+}
+
+// WriteImage plots the ellipse line with size number of points and writes it as an image of the given format to w.
+// Supported formats are: "png", "svg" and "pdf".
+// The width and height of the image are determined by width and height.
+// It returns error if the format is not supported or if the ellipse line could not be plotted or written to w.
+func (e *Ellipse) WriteImage(w io.Writer, format string, width, height vg.Length, size int) error {
+	switch format {
+	case "png", "svg", "pdf":
+	default:
+		return fmt.Errorf("Unsupported image format: %s", format)
+	}
+
+	p, err := plot.New()
+	if err != nil {
+		return err
+	}
+
+	line, _, err := e.LinePoints(size)
+	if err != nil {
+		return err
+	}
+	p.Add(line)
+
+	c, err := p.WriterTo(width, height, format)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.WriteTo(w)
+	return err
+}
@@ -0,0 +1,50 @@
+package ellipse
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestWritePNG(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{a: 1.0, b: 3.0}
+
+	var buf bytes.Buffer
+	err := ell.WritePNG(&buf, 2*vg.Inch, 2*vg.Inch, 100)
+	assert.NoError(err)
+	assert.True(bytes.HasPrefix(buf.Bytes(), []byte("\x89PNG\r\n\x1a\n")))
+}
+
+func TestWriteImage(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := Ellipse{a: 1.0, b: 3.0}
+
+	testCases := []struct {
+		format string
+		prefix string
+		err    bool
+	}{
+		{"png", "\x89PNG\r\n\x1a\n", false},
+		{"svg", "<?xml", false},
+		{"pdf", "%PDF", false},
+		{"jpg", "", true},
+		{"foo", "", true},
+	}
+
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		err := ell.WriteImage(&buf, tc.format, 2*vg.Inch, 2*vg.Inch, 100)
+		if !tc.err {
+			assert.NoError(err)
+			assert.True(bytes.HasPrefix(buf.Bytes(), []byte(tc.prefix)), tc.format)
+			continue
+		}
+		assert.Error(err)
+		assert.Zero(buf.Len())
+	}
+}
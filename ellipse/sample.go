@@ -0,0 +1,81 @@
+package ellipse
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/plot/plotter"
+)
+
+// defaultSeed seeds the random source used when no source is supplied
+const defaultSeed = 1
+
+// newRand returns new rand.Rand which draws random numbers from src.
+// If src is nil, a source seeded with a fixed seed is used so the results are reproducible.
+func newRand(src rand.Source) *rand.Rand {
+	if src == nil {
+		src = rand.NewSource(defaultSeed)
+	}
+
+	return rand.New(src)
+}
+
+// SampleInterior returns n points sampled uniformly from the ellipse interior using src random source.
+// If src is nil, a source seeded with a fixed seed is used.
+func (e *Ellipse) SampleInterior(n int, src rand.Source) plotter.XYs {
+	rnd := newRand(src)
+	sin, cos := math.Sincos(e.angle)
+
+	pts := make(plotter.XYs, n)
+	for i := range pts {
+		// square root of the radius ensures uniform distribution over the area
+		r := math.Sqrt(rnd.Float64())
+		theta := 2 * math.Pi * rnd.Float64()
+		x := e.a * r * math.Cos(theta)
+		y := e.b * r * math.Sin(theta)
+		pts[i].X = x*cos - y*sin + e.x
+		pts[i].Y = x*sin + y*cos + e.y
+	}
+
+	return pts
+}
+
+// SampleBoundary returns n points sampled from the ellipse boundary using src random source.
+// The points are sampled uniformly in the parametric angle of the ellipse, not in the arc length.
+// If src is nil, a source seeded with a fixed seed is used.
+func (e *Ellipse) SampleBoundary(n int, src rand.Source) plotter.XYs {
+	rnd := newRand(src)
+
+	pts := make(plotter.XYs, n)
+	for i := range pts {
+		pts[i].X, pts[i].Y = e.PointAt(2 * math.Pi * rnd.Float64())
+	}
+
+	return pts
+}
+
+// AngleHistogram samples size number of ellipse boundary points, spread uniformly in the ellipse parameter,
+// and returns the histogram of polar angles of the sampled points around the ellipse center.
+// The polar angles are binned into bins number of equally sized buckets covering the <0, 2*pi) interval.
+// It returns nil if either size or bins is not positive.
+func (e *Ellipse) AngleHistogram(size, bins int) []int {
+	if size <= 0 || bins <= 0 {
+		return nil
+	}
+
+	hist := make([]int, bins)
+	// the last boundary point is the same as the first one
+	for _, p := range e.boundary(size + 1)[:size] {
+		angle := math.Atan2(p.Y-e.y, p.X-e.x)
+		if angle < 0 {
+			angle += 2 * math.Pi
+		}
+		bin := int(angle / (2 * math.Pi) * float64(bins))
+		if bin >= bins {
+			bin = bins - 1
+		}
+		hist[bin]++
+	}
+
+	return hist
+}
@@ -0,0 +1,81 @@
+package ellipse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/rand"
+)
+
+func TestAngleHistogram(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: -1.0, a: 5.0, b: 1.0}
+	size, bins := 1000, 8
+
+	hist := ell.AngleHistogram(size, bins)
+	assert.Len(hist, bins)
+
+	total := 0
+	for _, c := range hist {
+		total += c
+	}
+	assert.Equal(size, total)
+
+	// the bins around major axis vertices must contain more points than the bins around minor axis vertices
+	assert.True(hist[0] > hist[1])
+	assert.True(hist[7] > hist[6])
+	assert.True(hist[3] > hist[2])
+	assert.True(hist[4] > hist[5])
+
+	// circle points are distributed uniformly
+	circle := &Ellipse{a: 2.0, b: 2.0, angle: math.Pi / 3}
+	hist = circle.AngleHistogram(size, bins)
+	for i := 1; i < bins; i++ {
+		assert.InDelta(hist[0], hist[i], 2)
+	}
+
+	// every boundary vertex is counted only once
+	unit := &Ellipse{a: 1.0, b: 1.0}
+	assert.Equal([]int{1, 1, 1, 1, 1, 1, 1, 1}, unit.AngleHistogram(8, 8))
+	assert.Equal([]int{1}, unit.AngleHistogram(1, 1))
+
+	assert.Nil(ell.AngleHistogram(size, 0))
+	assert.Nil(ell.AngleHistogram(0, bins))
+	assert.Nil(ell.AngleHistogram(-1, bins))
+}
+
+func TestSampleInterior(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: -1.0, a: 5.0, b: 1.0, angle: math.Pi / 3}
+	n := 100
+
+	pts := ell.SampleInterior(n, rand.NewSource(10))
+	assert.Len(pts, n)
+	for _, p := range pts {
+		assert.True(ell.Contains(p.X, p.Y))
+	}
+
+	assert.Equal(pts, ell.SampleInterior(n, rand.NewSource(10)))
+	assert.NotEqual(pts, ell.SampleInterior(n, rand.NewSource(20)))
+	assert.Equal(ell.SampleInterior(n, nil), ell.SampleInterior(n, nil))
+}
+
+func TestSampleBoundary(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: -1.0, a: 5.0, b: 1.0, angle: math.Pi / 3}
+	n := 100
+
+	pts := ell.SampleBoundary(n, rand.NewSource(10))
+	assert.Len(pts, n)
+	for _, p := range pts {
+		assert.True(ell.OnBoundary(p.X, p.Y, 1e-9))
+	}
+
+	assert.Equal(pts, ell.SampleBoundary(n, rand.NewSource(10)))
+	assert.NotEqual(pts, ell.SampleBoundary(n, rand.NewSource(20)))
+	assert.Equal(ell.SampleBoundary(n, nil), ell.SampleBoundary(n, nil))
+}
@@ -0,0 +1,15 @@
+package ellipse
+
+// Shape is a planar shape
+type Shape interface {
+	// Area returns the area of the shape
+	Area() float64
+	// Perimeter returns the perimeter of the shape
+	Perimeter() float64
+	// Contains returns true if the point [x,y] lies inside the shape
+	Contains(x, y float64) bool
+	// BoundingBox returns the axis aligned bounding box of the shape as minX, minY, maxX, maxY
+	BoundingBox() (float64, float64, float64, float64)
+}
+
+var _ Shape = (*Ellipse)(nil)
@@ -0,0 +1,34 @@
+package ellipse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShape(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		s         Shape
+		area      float64
+		perimeter float64
+		in        [2]float64
+		out       [2]float64
+		box       [4]float64
+	}{
+		{&Ellipse{a: 2.0, b: 2.0}, 4 * math.Pi, 4 * math.Pi, [2]float64{1.0, 1.0}, [2]float64{2.0, 2.0}, [4]float64{-2.0, -2.0, 2.0, 2.0}},
+		{&Ellipse{x: 1.0, y: 1.0, a: 3.0, b: 1.0}, 3 * math.Pi, 13.3649, [2]float64{3.5, 1.0}, [2]float64{1.0, 2.5}, [4]float64{-2.0, 0, 4.0, 2.0}},
+		{&Ellipse{a: 3.0, b: 1.0, angle: math.Pi / 2}, 3 * math.Pi, 13.3649, [2]float64{0, 2.5}, [2]float64{2.5, 0}, [4]float64{-1.0, -3.0, 1.0, 3.0}},
+	}
+
+	for _, tc := range testCases {
+		assert.InDelta(tc.area, tc.s.Area(), 1e-9)
+		assert.InDelta(tc.perimeter, tc.s.Perimeter(), 1e-3)
+		assert.True(tc.s.Contains(tc.in[0], tc.in[1]))
+		assert.False(tc.s.Contains(tc.out[0], tc.out[1]))
+		minX, minY, maxX, maxY := tc.s.BoundingBox()
+		assert.InDeltaSlice(tc.box[:], []float64{minX, minY, maxX, maxY}, 1e-9)
+	}
+}
@@ -0,0 +1,53 @@
+package ellipse
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// SVGPath returns SVG path data which approximates the ellipse boundary by 4 cubic Bezier curves.
+// It is equivalent to SVGPathN(4).
+func (e *Ellipse) SVGPath() string {
+	return e.SVGPathN(4)
+}
+
+// SVGPathN returns SVG path data which approximates the ellipse boundary by segments number of cubic Bezier curves.
+// Each of the curves spans the same parametric angle and its control points are tangent to the ellipse at its endpoints.
+// The error of the approximation decreases with the sixth power of the number of segments: 4 segments are accurate
+// to about 0.03% of the ellipse size, which is plenty for small figures, but large ellipses may need more segments
+// to render smoothly at the cost of longer path data.
+// The path coordinates are the ellipse coordinates i.e. Y axis is not flipped to match the SVG coordinate system.
+// It panics if segments is smaller than 2: a single curve can not be tangent to the whole ellipse boundary.
+//
+// For more information see: https://www.w3.org/TR/SVG/paths.html#PathDataCubicBezierCommands
+func (e *Ellipse) SVGPathN(segments int) string {
+	if segments < 2 {
+		panic(fmt.Sprintf("Invalid number of segments: %d", segments))
+	}
+
+	sin, cos := math.Sincos(e.angle)
+	// point returns the boundary point and the boundary derivative at the parametric angle t
+	point := func(t float64) (x, y, dx, dy float64) {
+		sinT, cosT := math.Sincos(t)
+		u, v := e.a*cosT, e.b*sinT
+		du, dv := -e.a*sinT, e.b*cosT
+		return u*cos - v*sin + e.x, u*sin + v*cos + e.y, du*cos - dv*sin, du*sin + dv*cos
+	}
+
+	step := 2 * math.Pi / float64(segments)
+	// the length of the control point tangents which makes the curves approximate circular arcs best
+	k := 4.0 / 3.0 * math.Tan(step/4)
+
+	var sb strings.Builder
+	x0, y0, dx0, dy0 := point(0)
+	fmt.Fprintf(&sb, "M %g %g", x0, y0)
+	for i := 1; i <= segments; i++ {
+		x1, y1, dx1, dy1 := point(float64(i) * step)
+		fmt.Fprintf(&sb, " C %g %g %g %g %g %g", x0+k*dx0, y0+k*dy0, x1-k*dx1, y1-k*dy1, x1, y1)
+		x0, y0, dx0, dy0 = x1, y1, dx1, dy1
+	}
+	sb.WriteString(" Z")
+
+	return sb.String()
+}
@@ -0,0 +1,81 @@
+package ellipse
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// svgCurves parses the SVG path data generated by SVGPathN into the control points of its Bezier curves.
+// Each of the returned curves contains the coordinates of its start point, both control points and its end point.
+func svgCurves(path string) [][8]float64 {
+	fields := strings.Fields(strings.TrimSuffix(path, " Z"))
+	nums := []float64{}
+	for _, f := range fields {
+		if f == "M" || f == "C" {
+			continue
+		}
+		v, _ := strconv.ParseFloat(f, 64)
+		nums = append(nums, v)
+	}
+
+	var curves [][8]float64
+	for i := 2; i+6 <= len(nums); i += 6 {
+		var c [8]float64
+		copy(c[:], nums[i-2:i+6])
+		curves = append(curves, c)
+	}
+
+	return curves
+}
+
+func TestSVGPath(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 6}
+	assert.Equal(ell.SVGPathN(4), ell.SVGPath())
+
+	for _, segments := range []int{2, 4, 16} {
+		path := ell.SVGPathN(segments)
+		assert.Equal(segments, strings.Count(path, "C"))
+		assert.True(strings.HasPrefix(path, "M "))
+		assert.True(strings.HasSuffix(path, " Z"))
+
+		curves := svgCurves(path)
+		assert.Len(curves, segments)
+		// the curve endpoints lie on the ellipse
+		for _, c := range curves {
+			assert.InDelta(0, ell.Implicit(c[0], c[1]), 1e-9)
+			assert.InDelta(0, ell.Implicit(c[6], c[7]), 1e-9)
+		}
+	}
+
+	assert.Panics(func() { ell.SVGPathN(0) })
+	assert.Panics(func() { ell.SVGPathN(1) })
+}
+
+func TestSVGPathAccuracy(t *testing.T) {
+	assert := assert.New(t)
+
+	circle := &Ellipse{a: 1.0, b: 1.0}
+
+	// maxErr returns the largest radial error of the points sampled from the Bezier curves
+	maxErr := func(segments int) float64 {
+		res := 0.0
+		for _, c := range svgCurves(circle.SVGPathN(segments)) {
+			for _, t := range []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9} {
+				s := 1 - t
+				x := s*s*s*c[0] + 3*s*s*t*c[2] + 3*s*t*t*c[4] + t*t*t*c[6]
+				y := s*s*s*c[1] + 3*s*s*t*c[3] + 3*s*t*t*c[5] + t*t*t*c[7]
+				res = math.Max(res, math.Abs(math.Hypot(x, y)-1))
+			}
+		}
+		return res
+	}
+
+	assert.True(maxErr(4) < 1e-3)
+	assert.True(maxErr(16) < maxErr(4))
+}
@@ -0,0 +1,158 @@
+package ellipse
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Centered returns a copy of the ellipse centered at the origin.
+// The axes and the rotation angle of the returned ellipse are the same as those of e.
+func (e *Ellipse) Centered() *Ellipse {
+	c := *e
+	c.x, c.y = 0, 0
+
+	return &c
+}
+
+// Canonical returns a copy of the ellipse in its canonical form i.e. centered at the origin,
+// with zero rotation angle and a being the semi-major axis.
+// Congruent ellipses have identical canonical forms.
+func (e *Ellipse) Canonical() *Ellipse {
+	c := *e
+	c.x, c.y, c.angle = 0, 0, 0
+	if c.b > c.a {
+		c.a, c.b = c.b, c.a
+	}
+
+	return &c
+}
+
+// Rotate returns a copy of the ellipse rotated by delta radians around its center.
+func (e *Ellipse) Rotate(delta float64) *Ellipse {
+	c := *e
+	c.angle += delta
+
+	return &c
+}
+
+// RotateAboutPoint returns a copy of the ellipse rotated by delta radians around the pivot point [px,py].
+// Both the ellipse center and its rotation angle are rotated.
+func (e *Ellipse) RotateAboutPoint(px, py, delta float64) *Ellipse {
+	sin, cos := math.Sincos(delta)
+	dx, dy := e.x-px, e.y-py
+
+	c := e.Rotate(delta)
+	c.x = px + dx*cos - dy*sin
+	c.y = py + dx*sin + dy*cos
+
+	return c
+}
+
+// ScaleAboutPoint returns a copy of the ellipse scaled by s around the pivot point [px,py].
+// Both the distance of the ellipse center from the pivot and the ellipse axes are scaled.
+// It panics if s is not positive.
+func (e *Ellipse) ScaleAboutPoint(px, py, s float64) *Ellipse {
+	if !(s > 0) {
+		panic("Invalid scale factor")
+	}
+
+	c := *e
+	c.x = px + s*(e.x-px)
+	c.y = py + s*(e.y-py)
+	c.a *= s
+	c.b *= s
+
+	return &c
+}
+
+// WithArea returns a copy of the ellipse scaled about its center whose area is targetArea.
+// Both of the ellipse semi-axes are scaled by sqrt(targetArea/Area()), so the shape and the rotation angle are preserved.
+// It returns error if targetArea is not positive or if it is infinite.
+func (e *Ellipse) WithArea(targetArea float64) (*Ellipse, error) {
+	if !(targetArea > 0) || math.IsInf(targetArea, 1) {
+		return nil, fmt.Errorf("Invalid target area: %v", targetArea)
+	}
+
+	return e.ScaleAboutPoint(e.x, e.y, math.Sqrt(targetArea/e.Area())), nil
+}
+
+// ScaleXY returns a copy of the ellipse scaled by sx along X axis and by sy along Y axis.
+// The scaling is applied to the whole plane, so both the ellipse center and its shape are scaled;
+// the axes and the rotation angle of the scaled ellipse are extracted from the transformed conic matrix.
+// It returns error if either of the scale factors is not positive.
+func (e *Ellipse) ScaleXY(sx, sy float64) (*Ellipse, error) {
+	if !(sx > 0) || !(sy > 0) {
+		return nil, fmt.Errorf("Invalid scale factors: (sx: %.2f, sy: %.2f)", sx, sy)
+	}
+
+	// transformed conic matrix is H^-T * Q * H^-1, where H = diag(sx, sy, 1)
+	h := [3]float64{sx, sy, 1}
+	q := e.ConicMatrix()
+	for i := 0; i < 3; i++ {
+		for j := i; j < 3; j++ {
+			q.SetSym(i, j, q.At(i, j)/(h[i]*h[j]))
+		}
+	}
+
+	c, err := fromConic(q.At(0, 0), 2*q.At(0, 1), q.At(1, 1), 2*q.At(0, 2), 2*q.At(1, 2), q.At(2, 2))
+	if err != nil {
+		return nil, err
+	}
+	// Mahalanobis distance is invariant under linear transformations
+	c.scale = e.scale
+
+	return c, nil
+}
+
+// Lerp returns the ellipse linearly interpolated between the ellipses a and b at t, where t is in <0,1> interval.
+// The centers, the semi-axes and the rotation angles of the ellipses are interpolated independently.
+// The rotation angle is interpolated along the shortest arc, taking into account that the ellipse
+// is symmetric under a half-turn. The returned ellipse retains the covariance metadata only if
+// both of the ellipses carry the same metadata.
+func Lerp(a, b *Ellipse, t float64) *Ellipse {
+	// angle difference reduced into <-pi/2, pi/2> interval
+	delta := math.Mod(b.angle-a.angle, math.Pi)
+	switch {
+	case delta > math.Pi/2:
+		delta -= math.Pi
+	case delta < -math.Pi/2:
+		delta += math.Pi
+	}
+
+	c := &Ellipse{
+		x:     a.x + t*(b.x-a.x),
+		y:     a.y + t*(b.y-a.y),
+		a:     a.a + t*(b.a-a.a),
+		b:     a.b + t*(b.b-a.b),
+		angle: a.angle + t*delta,
+	}
+
+	if a.scale == b.scale {
+		c.scale = a.scale
+	}
+
+	return c
+}
+
+// AlignData transforms the points stored in the first two columns of data into the coordinate system
+// of the ellipse i.e. it translates the points by the negative ellipse center and rotates them by the negative ellipse angle.
+// In the returned coordinates the ellipse is centered at the origin with the a semi-axis aligned with X axis
+// and the b semi-axis aligned with Y axis. The returned matrix has the same number of rows as data and 2 columns.
+// It returns error if data has less than 2 columns.
+func (e *Ellipse) AlignData(data mat.Matrix) (*mat.Dense, error) {
+	rows, cols := data.Dims()
+	if cols < 2 {
+		return nil, fmt.Errorf("Invalid number of data columns: %d", cols)
+	}
+
+	aligned := mat.NewDense(rows, 2, nil)
+	for i := 0; i < rows; i++ {
+		u, v := e.local(data.At(i, 0), data.At(i, 1))
+		aligned.Set(i, 0, u)
+		aligned.Set(i, 1, v)
+	}
+
+	return aligned, nil
+}
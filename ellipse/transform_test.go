@@ -0,0 +1,223 @@
+package ellipse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCentered(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 3.0, y: -2.0, a: 1.0, b: 3.0, angle: math.Pi / 3}
+	c := ell.Centered()
+
+	assert.Equal(&Ellipse{a: 1.0, b: 3.0, angle: math.Pi / 3}, c)
+	// the original ellipse must not be modified
+	assert.Equal(3.0, ell.x)
+	assert.Equal(-2.0, ell.y)
+}
+
+func TestCanonical(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		e   *Ellipse
+		exp *Ellipse
+	}{
+		{&Ellipse{x: 3.0, y: -2.0, a: 1.0, b: 3.0, angle: math.Pi / 3}, &Ellipse{a: 3.0, b: 1.0}},
+		{&Ellipse{x: -1.0, y: 5.0, a: 3.0, b: 1.0, angle: -math.Pi / 6}, &Ellipse{a: 3.0, b: 1.0}},
+		{&Ellipse{a: 2.0, b: 2.0}, &Ellipse{a: 2.0, b: 2.0}},
+	}
+
+	for _, tc := range testCases {
+		c := tc.e.Canonical()
+		assert.Equal(tc.exp, c)
+		assert.True(c.a >= c.b)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 3.0, y: -2.0, a: 1.0, b: 3.0, angle: math.Pi / 3}
+	r := ell.Rotate(math.Pi / 6)
+
+	assert.Equal(&Ellipse{x: 3.0, y: -2.0, a: 1.0, b: 3.0, angle: ell.angle + math.Pi/6}, r)
+	assert.Equal(math.Pi/3, ell.angle)
+}
+
+func TestRotateAboutPoint(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 3.0, y: -2.0, a: 1.0, b: 3.0, angle: math.Pi / 3}
+
+	// rotating about the ellipse center is a plain rotation
+	assert.True(ell.Rotate(0.5).ApproxEqual(ell.RotateAboutPoint(ell.x, ell.y, 0.5), 1e-12))
+
+	// full turn
+	assert.True(ell.ApproxEqual(ell.RotateAboutPoint(1.0, 1.0, 2*math.Pi), 1e-9))
+
+	// quarter turn about the origin
+	r := ell.RotateAboutPoint(0, 0, math.Pi/2)
+	assert.True(r.ApproxEqual(&Ellipse{x: 2.0, y: 3.0, a: 1.0, b: 3.0, angle: math.Pi/3 + math.Pi/2}, 1e-9))
+
+	// boundary points are rotated about the pivot
+	for _, theta := range []float64{0, 1.0, 2.0} {
+		x, y := ell.PointAt(theta)
+		rx, ry := r.PointAt(theta)
+		assert.InDelta(-y, rx, 1e-9)
+		assert.InDelta(x, ry, 1e-9)
+	}
+}
+
+func TestScaleAboutPoint(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 3.0, y: -2.0, a: 1.0, b: 3.0, angle: math.Pi / 3}
+
+	// scaling about the ellipse center only changes the axes
+	s := ell.ScaleAboutPoint(ell.x, ell.y, 2.0)
+	assert.Equal(&Ellipse{x: 3.0, y: -2.0, a: 2.0, b: 6.0, angle: math.Pi / 3}, s)
+
+	s = ell.ScaleAboutPoint(1.0, 1.0, 0.5)
+	assert.True(s.ApproxEqual(&Ellipse{x: 2.0, y: -0.5, a: 0.5, b: 1.5, angle: math.Pi / 3}, 1e-12))
+
+	// boundary points are scaled about the pivot
+	for _, theta := range []float64{0, 1.0, 2.0} {
+		x, y := ell.PointAt(theta)
+		sx, sy := s.PointAt(theta)
+		assert.InDelta(1.0+0.5*(x-1.0), sx, 1e-9)
+		assert.InDelta(1.0+0.5*(y-1.0), sy, 1e-9)
+	}
+
+	assert.Panics(func() { ell.ScaleAboutPoint(0, 0, 0) })
+	assert.Panics(func() { ell.ScaleAboutPoint(0, 0, -1.0) })
+}
+
+func TestWithArea(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 3.0, y: -2.0, a: 1.0, b: 3.0, angle: math.Pi / 3}
+
+	for _, area := range []float64{0.1, 1.0, ell.Area(), 50.0} {
+		s, err := ell.WithArea(area)
+		assert.NoError(err)
+		assert.InDelta(area, s.Area(), 1e-9)
+		assert.InDelta(ell.b/ell.a, s.b/s.a, 1e-12)
+		assert.Equal(ell.x, s.x)
+		assert.Equal(ell.y, s.y)
+		assert.Equal(ell.angle, s.angle)
+	}
+
+	// the original ellipse is not modified
+	assert.Equal(&Ellipse{x: 3.0, y: -2.0, a: 1.0, b: 3.0, angle: math.Pi / 3}, ell)
+
+	for _, area := range []float64{0, -1.0, math.NaN(), math.Inf(1)} {
+		s, err := ell.WithArea(area)
+		assert.Error(err)
+		assert.Nil(s)
+	}
+}
+
+func TestScaleXY(t *testing.T) {
+	assert := assert.New(t)
+
+	// scaling a circle yields an axis aligned ellipse
+	circle := &Ellipse{x: 1.0, y: 1.0, a: 2.0, b: 2.0}
+	s, err := circle.ScaleXY(2.0, 1.0)
+	assert.NoError(err)
+	assert.True(s.ApproxEqual(&Ellipse{x: 2.0, y: 1.0, a: 4.0, b: 2.0}, 1e-9))
+
+	s, err = circle.ScaleXY(1.0, 3.0)
+	assert.NoError(err)
+	assert.True(s.ApproxEqual(&Ellipse{x: 1.0, y: 3.0, a: 6.0, b: 2.0, angle: math.Pi / 2}, 1e-9))
+
+	// scaled boundary points lie on the scaled ellipse
+	ell := &Ellipse{x: 3.0, y: -2.0, a: 1.0, b: 3.0, angle: math.Pi / 3}
+	s, err = ell.ScaleXY(2.0, 0.5)
+	assert.NoError(err)
+	for _, theta := range []float64{0, 1.0, 2.0, 4.0} {
+		x, y := ell.PointAt(theta)
+		assert.InDelta(0, s.Implicit(2*x, 0.5*y), 1e-9)
+	}
+	assert.InDelta(ell.Area(), s.Area(), 1e-9)
+
+	for _, f := range [][2]float64{{0, 1.0}, {1.0, -1.0}} {
+		s, err := ell.ScaleXY(f[0], f[1])
+		assert.Error(err)
+		assert.Nil(s)
+	}
+}
+
+func TestLerp(t *testing.T) {
+	assert := assert.New(t)
+
+	a := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: 0.1, scale: 2.0}
+	b := &Ellipse{x: -3.0, y: 4.0, a: 5.0, b: 2.0, angle: math.Pi - 0.1, scale: 2.0}
+
+	assert.True(a.ApproxEqual(Lerp(a, b, 0), 1e-12))
+	assert.True(b.ApproxEqual(Lerp(a, b, 1), 1e-12))
+
+	mid := Lerp(a, b, 0.5)
+	assert.InDelta(-1.0, mid.x, 1e-12)
+	assert.InDelta(3.0, mid.y, 1e-12)
+	assert.InDelta(4.0, mid.a, 1e-12)
+	assert.InDelta(1.5, mid.b, 1e-12)
+	// the shortest arc between the angles passes through zero
+	assert.InDelta(0, mid.NormalizedAngle(), 1e-12)
+	assert.Equal(2.0, mid.scale)
+
+	b.scale = 0
+	assert.Equal(0.0, Lerp(a, b, 0.5).scale)
+}
+
+func TestAlignData(t *testing.T) {
+	assert := assert.New(t)
+
+	ell := &Ellipse{x: 1.0, y: 2.0, a: 3.0, b: 1.0, angle: math.Pi / 3}
+
+	thetas := []float64{0, math.Pi / 2, math.Pi, 3 * math.Pi / 2}
+	data := mat.NewDense(len(thetas)+1, 3, nil)
+	for i, theta := range thetas {
+		x, y := ell.PointAt(theta)
+		data.Set(i, 0, x)
+		data.Set(i, 1, y)
+	}
+	// the ellipse center
+	data.Set(len(thetas), 0, ell.x)
+	data.Set(len(thetas), 1, ell.y)
+
+	aligned, err := ell.AlignData(data)
+	assert.NoError(err)
+
+	rows, cols := aligned.Dims()
+	assert.Equal(len(thetas)+1, rows)
+	assert.Equal(2, cols)
+
+	exp := []float64{
+		3.0, 0,
+		0, 1.0,
+		-3.0, 0,
+		0, -1.0,
+		0, 0,
+	}
+	for i, v := range exp {
+		assert.InDelta(v, aligned.At(i/2, i%2), 1e-9)
+	}
+
+	// the aligned points lie on the canonical ellipse
+	canonical := ell.Canonical()
+	for _, theta := range []float64{0.3, 1.7, 4.1} {
+		x, y := ell.PointAt(theta)
+		aligned, err := ell.AlignData(mat.NewDense(1, 2, []float64{x, y}))
+		assert.NoError(err)
+		assert.InDelta(0, canonical.Implicit(aligned.At(0, 0), aligned.At(0, 1)), 1e-9)
+	}
+
+	aligned, err = ell.AlignData(mat.NewDense(3, 1, []float64{1.0, 2.0, 3.0}))
+	assert.Error(err)
+	assert.Nil(aligned)
+}